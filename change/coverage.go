@@ -0,0 +1,101 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/target"
+	"gotofu.com/mochi/utils/git"
+)
+
+// Uncovered returns the targets whose paths were changed since the given
+// revision without a release note entry for them being added.
+func Uncovered(rev string) ([]*domain.Target, error) {
+	var (
+		changedFiles []string
+		covered      = make(map[string]bool)
+	)
+
+	files, err := git.ChangedFiles(rev, "")
+	if err != nil {
+		return nil, err
+	}
+
+	addedFiles, err := git.ChangedFiles(rev, "A")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		if !isChangeFile(file) {
+			changedFiles = append(changedFiles, file)
+		}
+	}
+
+	for _, file := range addedFiles {
+		if !isChangeFile(file) {
+			continue
+		}
+
+		data, err := os.ReadFile(file)
+		if err != nil {
+			slog.Debug("Error reading added change file.", "file", file, "error", err)
+			continue
+		}
+
+		c, err := Parse(string(data))
+		if err != nil {
+			slog.Debug("Error parsing added change file.", "file", file, "error", err)
+			continue
+		}
+
+		for _, t := range c.Targets {
+			covered[t.Id] = true
+		}
+	}
+
+	var uncovered []*domain.Target
+	for _, t := range target.Touched(changedFiles) {
+		slog.Debug("Target touched.", "target", t.Id, "covered", covered[t.Id])
+		if !covered[t.Id] {
+			uncovered = append(uncovered, t)
+		}
+	}
+
+	return uncovered, nil
+}
+
+// HasSkipMarker reports whether the commit messages contain the marker on a
+// line of its own, which opts out of the coverage check.
+func HasSkipMarker(messages string, marker string) bool {
+	for _, line := range strings.Split(messages, "\n") {
+		if strings.TrimSpace(line) == marker {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isChangeFile(file string) bool {
+	return strings.HasPrefix(file, Directory+"/") && filepath.Ext(file) == ".md"
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change
+
+import "testing"
+
+func TestHasSkipMarker(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages string
+		want     bool
+	}{
+		{
+			name:     "marker line",
+			messages: "fix: typo\n\nskip-changelog\n",
+			want:     true,
+		},
+		{
+			name:     "marker line with spaces",
+			messages: "fix: typo\n\n  skip-changelog \n",
+			want:     true,
+		},
+		{
+			name:     "marker in another commit",
+			messages: "feat: add a setting\n\nfix: typo\n\nskip-changelog\n",
+			want:     true,
+		},
+		{
+			name:     "marker within a line",
+			messages: "docs: explain when to use skip-changelog\n",
+			want:     false,
+		},
+		{
+			name:     "marker as part of a word",
+			messages: "fix: typo\n\nskip-changelogs\n",
+			want:     false,
+		},
+		{
+			name:     "no marker",
+			messages: "fix: typo\n",
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HasSkipMarker(tt.messages, "skip-changelog"); got != tt.want {
+				t.Errorf("HasSkipMarker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/utils/editor"
+)
+
+var ErrEmptyMessage = errors.New("aborting change due to empty message")
+
+// scissors marks the start of the instructions appended to the change being
+// edited, like in git commit messages, so that lines starting with '#' in the
+// message, e.g. Markdown headings, are kept.
+const scissors = "# ------------------------ >8 ------------------------"
+
+const editComment = `
+` + scissors + `
+# Do not modify or remove the line above. Everything below it will be ignored,
+# and an empty message aborts the change.
+`
+
+func Edit(c *domain.Change) (*domain.Change, error) {
+	file, err := os.CreateTemp("", "mochi-*.md")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	if err := c.Render(file); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.WriteString(editComment); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	if err := editor.Open(file.Name()); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	rawChange := stripInstructions(string(data))
+	if matches := regex.FindStringSubmatch(rawChange); matches != nil && strings.TrimSpace(matches[2]) == "" {
+		return nil, ErrEmptyMessage
+	}
+
+	edited, err := Parse(rawChange)
+	if err != nil {
+		return nil, fmt.Errorf("invalid change: %w", err)
+	}
+
+	return edited, nil
+}
+
+func stripInstructions(rawChange string) string {
+	for offset := 0; offset < len(rawChange); {
+		line, _, _ := strings.Cut(rawChange[offset:], "\n")
+		if strings.TrimRight(line, "\r") == scissors {
+			return rawChange[:offset]
+		}
+		offset += len(line) + 1
+	}
+
+	return rawChange
+}
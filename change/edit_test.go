@@ -0,0 +1,56 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change
+
+import "testing"
+
+func TestStripInstructions(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{
+			name: "instructions are removed",
+			raw:  "---\ntype: doc\n---\n\nMessage\n" + editComment,
+			want: "---\ntype: doc\n---\n\nMessage\n\n",
+		},
+		{
+			name: "headings and shell comments are kept",
+			raw:  "---\ntype: doc\n---\n\nMessage\n\n# Breaking\n\n## Migration\n\n```sh\n# run this\nmochi check\n```\n" + editComment,
+			want: "---\ntype: doc\n---\n\nMessage\n\n# Breaking\n\n## Migration\n\n```sh\n# run this\nmochi check\n```\n\n",
+		},
+		{
+			name: "without instructions",
+			raw:  "---\ntype: doc\n---\n\n# Heading\n",
+			want: "---\ntype: doc\n---\n\n# Heading\n",
+		},
+		{
+			name: "with CRLF line endings",
+			raw:  "---\r\ntype: doc\r\n---\r\n\r\nMessage\r\n" + scissors + "\r\n# ignored\r\n",
+			want: "---\r\ntype: doc\r\n---\r\n\r\nMessage\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stripInstructions(tt.raw); got != tt.want {
+				t.Errorf("stripInstructions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
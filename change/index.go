@@ -0,0 +1,124 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gotofu.com/mochi/domain"
+)
+
+type InvalidChange struct {
+	File      string
+	TargetIds []string
+	Errors    ParseErrors
+}
+
+func (i InvalidChange) Diagnostics() []string {
+	var diagnostics []string
+	for _, err := range i.Errors {
+		diagnostics = append(diagnostics, fmt.Sprintf("%s:%d: %s", i.File, err.Line, err.Message))
+	}
+
+	return diagnostics
+}
+
+type Index struct {
+	changes map[string]map[string][]*domain.ReleaseChange
+	Invalid []*InvalidChange
+}
+
+func LoadIndex() (*Index, error) {
+	index := Index{
+		changes: make(map[string]map[string][]*domain.ReleaseChange),
+	}
+
+	// Repositories using the default configuration may not have a change
+	// directory yet, which means there are no changes.
+	entries, err := os.ReadDir(Directory)
+	if errors.Is(err, fs.ErrNotExist) {
+		slog.Debug("Change directory not found.", "directory", Directory)
+		return &index, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read change directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".md" {
+			continue
+		}
+
+		file := filepath.Join(Directory, entry.Name())
+		data, err := os.ReadFile(file)
+		if err != nil {
+			slog.Debug("Error reading change file.", "file", file, "error", err)
+			index.Invalid = append(index.Invalid, &InvalidChange{
+				File:   file,
+				Errors: ParseErrors{{Line: 1, Message: err.Error()}},
+			})
+			continue
+		}
+
+		c, targetIds, errs := parse(string(data))
+		if len(errs) > 0 {
+			slog.Debug("Error parsing change file.", "file", file, "error", errs)
+			index.Invalid = append(index.Invalid, &InvalidChange{
+				File:      file,
+				TargetIds: targetIds,
+				Errors:    errs,
+			})
+			continue
+		}
+
+		for _, t := range c.Targets {
+			if index.changes[t.Id] == nil {
+				index.changes[t.Id] = make(map[string][]*domain.ReleaseChange)
+			}
+			index.changes[t.Id][c.Type.Id] = append(index.changes[t.Id][c.Type.Id], &domain.ReleaseChange{
+				Change: c,
+				File:   file,
+			})
+		}
+	}
+
+	slog.Debug("Change files indexed.", "targets", len(index.changes), "invalid", len(index.Invalid))
+
+	return &index, nil
+}
+
+func (i Index) Get(targetId string, typeId string) []*domain.ReleaseChange {
+	return i.changes[targetId][typeId]
+}
+
+// InvalidFor returns the invalid changes declaring the given target, as well
+// as those whose targets could not be determined at all.
+func (i Index) InvalidFor(targetId string) []*InvalidChange {
+	var invalid []*InvalidChange
+	for _, c := range i.Invalid {
+		if len(c.TargetIds) == 0 || slices.Contains(c.TargetIds, targetId) {
+			invalid = append(invalid, c)
+		}
+	}
+
+	return invalid
+}
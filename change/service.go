@@ -17,58 +17,254 @@ limitations under the License.
 package change
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"gotofu.com/mochi/change_type"
 	"gotofu.com/mochi/domain"
 	"gotofu.com/mochi/target"
+	"gotofu.com/mochi/utils/git"
 
 	"gopkg.in/yaml.v3"
 )
 
-func Commit(c *domain.Change) error {
-	fileName := fmt.Sprintf(".mochi/%s", c.Filename())
+const Directory = ".mochi"
+
+func Commit(c *domain.Change) (string, error) {
+	fileName := filepath.Join(Directory, c.Filename())
+	file, err := os.OpenFile(fileName, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return "", fmt.Errorf("change file %s already exists", fileName)
+	} else if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	return fileName, c.Render(file)
+}
+
+func Write(c *domain.Change, fileName string) error {
 	file, err := os.Create(fileName)
 	if err != nil {
 		return err
 	}
+	defer file.Close()
 
 	return c.Render(file)
 }
 
+func Read(fileName string) (*domain.Change, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read change file %s: %w", fileName, err)
+	}
+
+	c, err := Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse change file %s: %w", fileName, err)
+	}
+
+	return c, nil
+}
+
 type ChangeMeta struct {
-	Target string
-	Type   string
+	Target   string
+	Targets  []string
+	Type     string
+	Metadata map[string]string
+	Issues   []string
+	PR       string
+	Authors  []string
+	Breaking bool
+}
+
+type ParseError struct {
+	Line    int
+	Message string
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+type ParseErrors []ParseError
+
+func (e ParseErrors) Error() string {
+	var messages []string
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "; ")
 }
 
-var regex = regexp.MustCompile(`(?s)^---\r?\n(.*?)\r?\n---\r?\n(.*)$`)
+var (
+	regex          = regexp.MustCompile(`(?s)^---\r?\n(.*?)\r?\n---\r?\n(.*)$`)
+	yamlErrorRegex = regexp.MustCompile(`line (\d+): (.*)`)
+)
+
+// The frontmatter starts on the line after the opening delimiter.
+const frontmatterOffset = 1
 
 func Parse(rawChange string) (*domain.Change, error) {
+	c, _, errs := parse(rawChange)
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return c, nil
+}
+
+// parse also returns the declared target IDs, so that callers can attribute
+// an invalid change to the targets it was meant for.
+func parse(rawChange string) (*domain.Change, []string, ParseErrors) {
 	var (
-		c   domain.Change
-		m   ChangeMeta
-		err error
+		c           domain.Change
+		m           ChangeMeta
+		document    yaml.Node
+		targetLines []int
+		hasType     bool
+		errs        ParseErrors
 	)
 
 	matches := regex.FindStringSubmatch(rawChange)
 	if len(matches) != 3 {
-		return nil, fmt.Errorf("invalid change format")
+		return nil, nil, ParseErrors{{Line: 1, Message: "invalid change format, expected a frontmatter block delimited by ---"}}
+	}
+
+	if err := yaml.Unmarshal([]byte(matches[1]), &document); err != nil {
+		line, message := 1, err.Error()
+		if parts := yamlErrorRegex.FindStringSubmatch(message); parts != nil {
+			line, _ = strconv.Atoi(parts[1])
+			message = parts[2]
+		}
+		return nil, nil, ParseErrors{{Line: line + frontmatterOffset, Message: message}}
+	}
+
+	if len(document.Content) == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, ParseErrors{{Line: 1 + frontmatterOffset, Message: "frontmatter must be a mapping"}}
+	}
+
+	mapping := document.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key, value := mapping.Content[i], mapping.Content[i+1]
+		line := value.Line + frontmatterOffset
+
+		switch key.Value {
+		case "target":
+			if err := value.Decode(&m.Target); err != nil || m.Target == "" {
+				errs = append(errs, ParseError{Line: line, Message: "target must be a non-empty string"})
+			} else {
+				m.Targets = append([]string{m.Target}, m.Targets...)
+				targetLines = append([]int{line}, targetLines...)
+			}
+		case "targets":
+			if value.Kind != yaml.SequenceNode {
+				errs = append(errs, ParseError{Line: line, Message: "targets must be a list of strings"})
+				continue
+			}
+			for _, item := range value.Content {
+				var id string
+				if err := item.Decode(&id); err != nil || id == "" {
+					errs = append(errs, ParseError{Line: item.Line + frontmatterOffset, Message: "targets must be a list of strings"})
+					continue
+				}
+				m.Targets = append(m.Targets, id)
+				targetLines = append(targetLines, item.Line+frontmatterOffset)
+			}
+		case "type":
+			hasType = true
+			if err := value.Decode(&m.Type); err != nil || m.Type == "" {
+				errs = append(errs, ParseError{Line: line, Message: "type must be a non-empty string"})
+				continue
+			}
+			if c.Type, _ = change_type.Get(m.Type); c.Type == nil {
+				errs = append(errs, ParseError{Line: line, Message: fmt.Sprintf("unknown type %s, expected one of %s", m.Type, strings.Join(change_type.GetIds(), ", "))})
+			}
+		case "metadata":
+			if err := value.Decode(&m.Metadata); err != nil || value.Kind != yaml.MappingNode {
+				errs = append(errs, ParseError{Line: line, Message: "metadata must be a mapping of strings"})
+				continue
+			}
+			c.Metadata = m.Metadata
+		case "issues":
+			if err := value.Decode(&m.Issues); err != nil || value.Kind != yaml.SequenceNode {
+				errs = append(errs, ParseError{Line: line, Message: "issues must be a list of strings"})
+				continue
+			}
+			c.Issues = m.Issues
+		case "pr":
+			if err := value.Decode(&m.PR); err != nil || value.Kind != yaml.ScalarNode {
+				errs = append(errs, ParseError{Line: line, Message: "pr must be a string"})
+				continue
+			}
+			c.PR = m.PR
+		case "authors":
+			if err := value.Decode(&m.Authors); err != nil || value.Kind != yaml.SequenceNode {
+				errs = append(errs, ParseError{Line: line, Message: "authors must be a list of strings"})
+				continue
+			}
+			c.Authors = m.Authors
+		case "breaking":
+			if err := value.Decode(&m.Breaking); err != nil {
+				errs = append(errs, ParseError{Line: line, Message: "breaking must be a boolean"})
+				continue
+			}
+			c.Breaking = m.Breaking
+		default:
+			errs = append(errs, ParseError{Line: key.Line + frontmatterOffset, Message: fmt.Sprintf("unknown field %s", key.Value)})
+		}
+	}
+
+	if len(m.Targets) == 0 {
+		errs = append(errs, ParseError{Line: 1, Message: "change does not declare any target"})
+	}
+	for i, id := range m.Targets {
+		if slices.Contains(m.Targets[:i], id) {
+			errs = append(errs, ParseError{Line: targetLines[i], Message: fmt.Sprintf("target %s is declared more than once", id)})
+		} else if t, err := target.Get(id); err != nil {
+			errs = append(errs, ParseError{Line: targetLines[i], Message: fmt.Sprintf("unknown target %s, expected one of %s", id, strings.Join(target.GetIds(), ", "))})
+		} else {
+			c.Targets = append(c.Targets, t)
+		}
 	}
 
-	if err := yaml.Unmarshal([]byte(matches[1]), &m); err != nil {
-		return nil, err
+	if !hasType {
+		errs = append(errs, ParseError{Line: 1, Message: "change does not declare a type"})
 	}
 
 	c.Message = strings.TrimSpace(matches[2])
-	if c.Target, err = target.Get(m.Target); err != nil {
-		return nil, err
+	if c.Message == "" {
+		bodyLine := strings.Count(rawChange[:len(rawChange)-len(matches[2])], "\n") + 1
+		errs = append(errs, ParseError{Line: bodyLine, Message: "change message is empty"})
 	}
-	if c.Type, err = change_type.Get(m.Type); err != nil {
-		return nil, err
+
+	slices.SortStableFunc(errs, func(a, b ParseError) int {
+		return a.Line - b.Line
+	})
+
+	return &c, m.Targets, errs
+}
+
+func CreatedAt(file string) time.Time {
+	if createdAt, err := git.FileCreatedAt(file); err == nil {
+		return createdAt
+	} else {
+		slog.Debug("Falling back to the modification time of the change file.", "file", file, "error", err)
+	}
+
+	if info, err := os.Stat(file); err == nil {
+		return info.ModTime()
 	}
 
-	return &c, nil
+	return time.Time{}
 }
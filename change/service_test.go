@@ -0,0 +1,101 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package change
+
+import (
+	"slices"
+	"testing"
+
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/domain"
+)
+
+func TestParse(t *testing.T) {
+	config.Configuration = &config.Config{
+		Targets: []domain.Target{{Id: "api", Name: "API"}, {Id: "web", Name: "Web"}},
+		Types:   []domain.ChangeType{{Id: "bugfix", Name: "Bug fix"}, {Id: "feature", Name: "Feature"}},
+	}
+
+	tests := []struct {
+		name        string
+		raw         string
+		wantTargets []string
+		wantErrs    ParseErrors
+	}{
+		{
+			name:        "valid",
+			raw:         "---\ntargets: [api, web]\ntype: bugfix\n---\nFix a bug\n",
+			wantTargets: []string{"api", "web"},
+		},
+		{
+			name:     "missing frontmatter",
+			raw:      "Fix a bug\n",
+			wantErrs: ParseErrors{{Line: 1, Message: "invalid change format, expected a frontmatter block delimited by ---"}},
+		},
+		{
+			name:     "invalid yaml",
+			raw:      "---\ntarget: api\n\ttype: bugfix\n---\nFix a bug\n",
+			wantErrs: ParseErrors{{Line: 3, Message: "found a tab character that violates indentation"}},
+		},
+		{
+			name:     "frontmatter is not a mapping",
+			raw:      "---\n- api\n---\nFix a bug\n",
+			wantErrs: ParseErrors{{Line: 2, Message: "frontmatter must be a mapping"}},
+		},
+		{
+			name:        "unknown type",
+			raw:         "---\ntarget: api\ntype: fix\n---\nFix a bug\n",
+			wantTargets: []string{"api"},
+			wantErrs:    ParseErrors{{Line: 3, Message: "unknown type fix, expected one of bugfix, feature"}},
+		},
+		{
+			name:        "unknown target",
+			raw:         "---\ntype: bugfix\ntargets:\n  - api\n  - cli\n---\nFix a bug\n",
+			wantTargets: []string{"api", "cli"},
+			wantErrs:    ParseErrors{{Line: 5, Message: "unknown target cli, expected one of api, web"}},
+		},
+		{
+			name:        "duplicate target",
+			raw:         "---\ntarget: api\ntargets: [api]\ntype: bugfix\n---\nFix a bug\n",
+			wantTargets: []string{"api", "api"},
+			wantErrs:    ParseErrors{{Line: 3, Message: "target api is declared more than once"}},
+		},
+		{
+			name:        "several errors sorted by line",
+			raw:         "---\ntarget: api\nsummary: Fix\nbreaking: maybe\n---\n\n",
+			wantTargets: []string{"api"},
+			wantErrs: ParseErrors{
+				{Line: 1, Message: "change does not declare a type"},
+				{Line: 3, Message: "unknown field summary"},
+				{Line: 4, Message: "breaking must be a boolean"},
+				{Line: 6, Message: "change message is empty"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, targets, errs := parse(tt.raw)
+			if !slices.Equal(targets, tt.wantTargets) {
+				t.Errorf("parse() targets = %v, want %v", targets, tt.wantTargets)
+			}
+			if !slices.Equal(errs, tt.wantErrs) {
+				t.Errorf("parse() errors = %v, want %v", errs, tt.wantErrs)
+			}
+		})
+	}
+}
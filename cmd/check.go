@@ -0,0 +1,106 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gotofu.com/mochi/change"
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/utils/git"
+
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Validate the pending release note entries",
+	Long: `The "check" command validates every release note entry against the configured
+types and targets, and reports any problem as a file:line diagnostic.
+
+It exits with a non-zero status if any entry is invalid, so it can be used to gate pull requests.
+
+With --against, it also fails if any target whose paths were changed since the given revision
+has no new release note entry. Add the skip marker (by default "skip-changelog") on a line of its
+own to a commit message to opt out.`,
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		index, err := change.LoadIndex()
+		if err != nil {
+			return err
+		}
+
+		if len(index.Invalid) > 0 {
+			printDiagnostics(os.Stderr, index.Invalid)
+			return fmt.Errorf("%d invalid release note entries found", len(index.Invalid))
+		}
+
+		if against, _ := cmd.Flags().GetString("against"); against != "" {
+			if err := checkCoverage(against); err != nil {
+				return err
+			}
+		}
+
+		fmt.Println("All release note entries are valid.")
+
+		return nil
+	},
+}
+
+func checkCoverage(rev string) error {
+	if marker := config.Configuration.SkipMarker; marker != "" {
+		messages, err := git.CommitMessages(rev)
+		if err != nil {
+			return err
+		}
+		if change.HasSkipMarker(messages, marker) {
+			fmt.Printf("Skipping the release note coverage check because a commit contains a %q line.\n", marker)
+			return nil
+		}
+	}
+
+	uncovered, err := change.Uncovered(rev)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range uncovered {
+		fmt.Fprintf(os.Stderr, "target %s was changed but no release note entry was added for it\n", t.Id)
+	}
+	if len(uncovered) > 0 {
+		return fmt.Errorf("%d changed targets without a release note entry; run 'mochi new' to add one", len(uncovered))
+	}
+
+	return nil
+}
+
+func printDiagnostics(w io.Writer, invalid []*change.InvalidChange) {
+	for _, c := range invalid {
+		for _, diagnostic := range c.Diagnostics() {
+			fmt.Fprintln(w, diagnostic)
+		}
+	}
+}
+
+func init() {
+	checkCmd.Flags().String("against", "", "also require a new release note entry for every target changed since the given revision")
+
+	rootCmd.AddCommand(checkCmd)
+}
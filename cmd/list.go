@@ -0,0 +1,158 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"gotofu.com/mochi/change"
+	"gotofu.com/mochi/change_type"
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/release"
+	"gotofu.com/mochi/target"
+
+	"github.com/spf13/cobra"
+)
+
+type listEntry struct {
+	File      string    `json:"file"`
+	Target    string    `json:"target"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"createdAt"`
+	Summary   string    `json:"summary"`
+	Message   string    `json:"message"`
+}
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the pending release note entries",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			targets    []*domain.Target
+			changeType *domain.ChangeType
+			err        error
+		)
+
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return fmt.Errorf("unsupported output %s", output)
+		}
+
+		if targetFlag, _ := cmd.Flags().GetString("target"); targetFlag != "" {
+			if targets, err = target.GetAll([]string{targetFlag}); err != nil {
+				return err
+			}
+		} else {
+			for i := range config.Configuration.Targets {
+				targets = append(targets, &config.Configuration.Targets[i])
+			}
+		}
+
+		if typeFlag, _ := cmd.Flags().GetString("type"); typeFlag != "" {
+			if changeType, err = change_type.Get(typeFlag); err != nil {
+				return err
+			}
+		}
+
+		index, err := change.LoadIndex()
+		if err != nil {
+			return err
+		}
+
+		entries := []listEntry{}
+		for _, t := range targets {
+			for _, note := range release.GetFromIndex(index, t) {
+				if changeType != nil && note.Type.Id != changeType.Id {
+					continue
+				}
+
+				for _, releaseChange := range note.Changes {
+					entries = append(entries, listEntry{
+						File:      releaseChange.File,
+						Target:    t.Id,
+						Type:      note.Type.Id,
+						CreatedAt: change.CreatedAt(releaseChange.File),
+						Summary:   releaseChange.Change.Summary(),
+						Message:   releaseChange.Change.Message,
+					})
+				}
+			}
+		}
+
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(entries)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No release notes found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "FILE\tTARGET\tTYPE\tAGE\tMESSAGE")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.File, e.Target, e.Type, formatAge(e.CreatedAt), e.Summary)
+		}
+
+		return w.Flush()
+	},
+}
+
+func formatAge(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	switch age := time.Since(t); {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+func init() {
+	listCmd.Flags().StringP("target", "t", "", "only list entries for the given target")
+	listCmd.Flags().String("type", "", "only list entries of the given type")
+	listCmd.Flags().StringP("output", "o", "text", "the output format (text, json)")
+
+	listCmd.RegisterFlagCompletionFunc("target", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var comps []string
+		for _, t := range config.Configuration.Targets {
+			comps = append(comps, t.Id)
+		}
+		return comps, cobra.ShellCompDirectiveNoFileComp
+	})
+	listCmd.RegisterFlagCompletionFunc("type", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return change_type.GetIds(), cobra.ShellCompDirectiveNoFileComp
+	})
+	listCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+
+	rootCmd.AddCommand(listCmd)
+}
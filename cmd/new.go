@@ -17,20 +17,28 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
 
 	"gotofu.com/mochi/change"
 	"gotofu.com/mochi/change_type"
 	"gotofu.com/mochi/config"
 	"gotofu.com/mochi/domain"
 	"gotofu.com/mochi/target"
+	"gotofu.com/mochi/utils/git"
 
+	"github.com/chzyer/readline"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
 )
 
 var newCmd = &cobra.Command{
-	Use:   "new [type] [target] [message]",
+	Use:   "new [type] [target[,target...]] [message]",
 	Short: "Create a new release note entry",
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		var comps []string
@@ -41,8 +49,12 @@ var newCmd = &cobra.Command{
 				comps = append(comps, t.Id)
 			}
 		case 1:
+			prefix := ""
+			if i := strings.LastIndex(toComplete, ","); i >= 0 {
+				prefix = toComplete[:i+1]
+			}
 			for _, t := range config.Configuration.Targets {
-				comps = append(comps, t.Id)
+				comps = append(comps, prefix+t.Id)
 			}
 		case 2:
 			break
@@ -61,63 +73,341 @@ var newCmd = &cobra.Command{
 			err error
 		)
 
-		if len(args) > 0 {
-			if c.Type, err = change_type.Get(args[0]); err != nil {
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return fmt.Errorf("unsupported output %s", output)
+		}
+
+		if fromJSON, _ := cmd.Flags().GetString("from-json"); fromJSON != "" {
+			if len(args) > 0 {
+				return fmt.Errorf("arguments cannot be combined with --from-json")
+			}
+
+			if err := readChangeInput(fromJSON, &c); err != nil {
 				return err
 			}
 		} else {
-			prompt := promptui.Select{
-				Label:     "Choose the type of change",
-				Items:     config.Configuration.Types,
-				Templates: namedItemPromptTemplate,
-			}
-
-			if index, _, err := prompt.Run(); err != nil {
+			if err := promptChange(cmd, args, &c); err != nil {
 				return err
-			} else {
-				c.Type = &config.Configuration.Types[index]
 			}
 		}
 
-		if len(args) > 1 {
-			if c.Target, err = target.Get(args[1]); err != nil {
-				return err
-			}
+		if strings.TrimSpace(c.Message) == "" {
+			return change.ErrEmptyMessage
+		}
+
+		fileName, err := change.Commit(&c)
+		if err != nil {
+			return err
+		}
+
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(newChangeOutput{
+				File:    fileName,
+				Type:    c.Type.Id,
+				Targets: c.TargetIds(),
+			})
+		}
+
+		fmt.Println(fileName)
+
+		return nil
+	},
+}
+
+type newChangeInput struct {
+	Type     string            `json:"type"`
+	Target   string            `json:"target"`
+	Targets  []string          `json:"targets"`
+	Message  string            `json:"message"`
+	Metadata map[string]string `json:"metadata"`
+	Issues   []stringOrNumber  `json:"issues"`
+	PR       stringOrNumber    `json:"pr"`
+	Authors  []string          `json:"authors"`
+	Breaking bool              `json:"breaking"`
+}
+
+// stringOrNumber accepts issue and pull request numbers given either as JSON
+// strings or numbers.
+type stringOrNumber string
+
+func (s *stringOrNumber) UnmarshalJSON(data []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err == nil {
+		*s = stringOrNumber(number)
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	*s = stringOrNumber(str)
+
+	return nil
+}
+
+type newChangeOutput struct {
+	File    string   `json:"file"`
+	Type    string   `json:"type"`
+	Targets []string `json:"targets"`
+}
+
+func readChangeInput(path string, c *domain.Change) error {
+	var (
+		input newChangeInput
+		err   error
+	)
+
+	data, err := readInput(path)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&input); err != nil {
+		return fmt.Errorf("could not decode change: %w", err)
+	}
+
+	if input.Target != "" {
+		input.Targets = append([]string{input.Target}, input.Targets...)
+	}
+	if len(input.Targets) == 0 {
+		return fmt.Errorf("change does not declare any target")
+	}
+
+	if c.Type, err = change_type.Get(input.Type); err != nil {
+		return err
+	}
+	if c.Targets, err = target.GetAll(input.Targets); err != nil {
+		return err
+	}
+	c.Message = strings.TrimSpace(input.Message)
+	c.Metadata = input.Metadata
+	c.PR = string(input.PR)
+	c.Authors = input.Authors
+	c.Breaking = input.Breaking
+	for _, issue := range input.Issues {
+		c.Issues = append(c.Issues, string(issue))
+	}
+
+	if len(c.Authors) == 0 {
+		c.Authors = defaultAuthors()
+	}
+
+	return nil
+}
+
+func defaultAuthors() []string {
+	if name, err := git.UserName(); err == nil && name != "" {
+		return []string{name}
+	} else {
+		slog.Debug("Could not get the default author.", "error", err)
+	}
+
+	return nil
+}
+
+func promptReferences(cmd *cobra.Command, c *domain.Change, interactive bool) error {
+	c.Issues, _ = cmd.Flags().GetStringSlice("issue")
+	c.PR, _ = cmd.Flags().GetString("pr")
+	c.Authors, _ = cmd.Flags().GetStringSlice("author")
+	c.Breaking, _ = cmd.Flags().GetBool("breaking")
+
+	if !cmd.Flags().Changed("author") {
+		c.Authors = defaultAuthors()
+	}
+
+	if !interactive {
+		return nil
+	}
+
+	if !cmd.Flags().Changed("issue") {
+		prompt := promptui.Prompt{
+			Label: "Enter the related issues, separated by commas (optional)",
+		}
+
+		if issues, err := prompt.Run(); err != nil {
+			return err
 		} else {
-			prompt := promptui.Select{
-				Label:     "Choose the target affected by the change",
-				Items:     config.Configuration.Targets,
-				Templates: namedItemPromptTemplate,
-			}
+			c.Issues = splitList(issues)
+		}
+	}
 
-			if index, _, err := prompt.Run(); err != nil {
-				return err
-			} else {
-				c.Target = &config.Configuration.Targets[index]
-			}
+	if !cmd.Flags().Changed("pr") {
+		prompt := promptui.Prompt{
+			Label: "Enter the pull request (optional)",
 		}
 
-		if len(args) > 2 {
-			c.Message = args[2]
+		if pr, err := prompt.Run(); err != nil {
+			return err
 		} else {
-			prompt := promptui.Prompt{
-				Label: "Enter the message for the change",
-			}
+			c.PR = strings.TrimSpace(pr)
+		}
+	}
 
-			if c.Message, err = prompt.Run(); err != nil {
-				return err
-			}
+	if !cmd.Flags().Changed("author") {
+		prompt := promptui.Prompt{
+			Label:     "Enter the authors, separated by commas",
+			Default:   strings.Join(c.Authors, ", "),
+			AllowEdit: true,
 		}
 
-		if err := change.Commit(&c); err != nil {
+		if authors, err := prompt.Run(); err != nil {
 			return err
+		} else {
+			c.Authors = splitList(authors)
 		}
+	}
 
-		return nil
-	},
+	return nil
+}
+
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func promptChange(cmd *cobra.Command, args []string, c *domain.Change) error {
+	var err error
+
+	interactive := isInteractive()
+
+	if len(args) > 0 {
+		if c.Type, err = change_type.Get(args[0]); err != nil {
+			return err
+		}
+	} else if !interactive {
+		return fmt.Errorf("no type given; pass it as an argument or use --from-json when not running in a terminal")
+	} else {
+		prompt := promptui.Select{
+			Label:     "Choose the type of change",
+			Items:     config.Configuration.Types,
+			Templates: namedItemPromptTemplate,
+		}
+
+		if index, _, err := prompt.Run(); err != nil {
+			return err
+		} else {
+			c.Type = &config.Configuration.Types[index]
+		}
+	}
+
+	if len(args) > 1 {
+		if c.Targets, err = target.GetAll(strings.Split(args[1], ",")); err != nil {
+			return err
+		}
+	} else if !interactive {
+		return fmt.Errorf("no target given; pass it as an argument or use --from-json when not running in a terminal")
+	} else {
+		stagedFiles, err := git.StagedFiles()
+		if err != nil {
+			slog.Debug("Could not get staged files to infer the target.", "error", err)
+		}
+
+		inferredTargets := target.Infer(config.WorkingDirectory, stagedFiles)
+		slog.Debug("Targets inferred.", "count", len(inferredTargets))
+
+		if c.Targets, err = selectTargets(inferredTargets); err != nil {
+			return err
+		}
+	}
+
+	messageFile, _ := cmd.Flags().GetString("message-file")
+
+	// The edit setting only replaces the message prompt, so that changes
+	// given in full, e.g. by bots, never wait for an editor.
+	edit := config.Configuration.Edit && interactive && messageFile == "" && len(args) <= 2
+	if cmd.Flags().Changed("edit") {
+		edit, _ = cmd.Flags().GetBool("edit")
+	}
+
+	switch {
+	case messageFile != "":
+		if len(args) > 2 {
+			return fmt.Errorf("the message argument cannot be combined with --message-file")
+		}
+
+		data, err := readInput(messageFile)
+		if err != nil {
+			return err
+		}
+		c.Message = strings.TrimSpace(string(data))
+	case len(args) > 2:
+		c.Message = args[2]
+	}
+
+	if !edit && messageFile == "" && len(args) <= 2 {
+		if !interactive {
+			return fmt.Errorf("no message given; pass it as an argument or use --message-file when not running in a terminal")
+		}
+
+		prompt := promptui.Prompt{
+			Label: "Enter the message for the change",
+		}
+
+		if c.Message, err = prompt.Run(); err != nil {
+			return err
+		}
+	}
+
+	// Only prompt for the references when the change is not fully specified
+	// through the arguments, to keep the quick path free of prompts. When
+	// editing, they can be filled in the editor instead.
+	if err := promptReferences(cmd, c, interactive && !edit && len(args) <= 2 && messageFile == ""); err != nil {
+		return err
+	}
+
+	if edit {
+		if !interactive {
+			return fmt.Errorf("cannot open an editor when not running in a terminal")
+		}
+
+		edited, err := change.Edit(c)
+		if err != nil {
+			return err
+		}
+		*c = *edited
+	}
+
+	return nil
+}
+
+func readInput(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+
+	return os.ReadFile(config.ResolvePath(path))
+}
+
+func isInteractive() bool {
+	return readline.IsTerminal(int(os.Stdin.Fd()))
 }
 
 func init() {
+	newCmd.Flags().BoolP("edit", "e", false, "open $VISUAL or $EDITOR to write the message (defaults to the edit setting)")
+	newCmd.Flags().String("from-json", "", "read the type, targets, message and metadata from a JSON file (- for stdin)")
+	newCmd.Flags().StringP("message-file", "F", "", "read the message from a file (- for stdin)")
+	newCmd.Flags().StringP("output", "o", "text", "the output format (text, json)")
+	newCmd.Flags().StringSlice("issue", nil, "the issues related to the change")
+	newCmd.Flags().String("pr", "", "the pull request introducing the change")
+	newCmd.Flags().StringSlice("author", nil, "the authors of the change (defaults to the git user name)")
+	newCmd.Flags().Bool("breaking", false, "mark the change as breaking, requiring a major version bump")
+
+	newCmd.MarkFlagsMutuallyExclusive("from-json", "message-file")
+	newCmd.MarkFlagsMutuallyExclusive("from-json", "edit")
+	newCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+
 	rootCmd.AddCommand(newCmd)
 }
 
@@ -127,3 +417,70 @@ var namedItemPromptTemplate = &promptui.SelectTemplates{
 	Inactive: "  {{ .Name }}",
 	Selected: fmt.Sprintf(`{{ "%s" | green }} {{ .Name | faint }}`, promptui.IconGood),
 }
+
+type selectableTarget struct {
+	Name     string
+	Selected bool
+}
+
+var selectableTargetPromptTemplate = &promptui.SelectTemplates{
+	Label:    fmt.Sprintf("%s {{ .Name }}: ", promptui.IconInitial),
+	Active:   fmt.Sprintf("%s {{ if .Selected }}[x]{{ else }}[ ]{{ end }} {{ .Name | underline }}", promptui.IconSelect),
+	Inactive: "  {{ if .Selected }}[x]{{ else }}[ ]{{ end }} {{ .Name }}",
+	Selected: fmt.Sprintf(`{{ "%s" | green }} {{ .Name | faint }}`, promptui.IconGood),
+}
+
+func selectTargets(preselected []*domain.Target) ([]*domain.Target, error) {
+	selected := make([]bool, len(config.Configuration.Targets))
+	cursor := 0
+
+	for i, t := range config.Configuration.Targets {
+		for _, p := range preselected {
+			if p.Id == t.Id {
+				selected[i] = true
+				cursor = len(config.Configuration.Targets)
+			}
+		}
+	}
+
+	for {
+		var items []selectableTarget
+		for i, t := range config.Configuration.Targets {
+			items = append(items, selectableTarget{Name: t.Name, Selected: selected[i]})
+		}
+		items = append(items, selectableTarget{Name: "Done"})
+
+		prompt := promptui.Select{
+			Label:        "Choose the targets affected by the change",
+			Items:        items,
+			Templates:    selectableTargetPromptTemplate,
+			CursorPos:    cursor,
+			HideSelected: true,
+		}
+
+		index, _, err := prompt.Run()
+		if err != nil {
+			return nil, err
+		}
+
+		if index < len(config.Configuration.Targets) {
+			selected[index] = !selected[index]
+			cursor = index
+			continue
+		}
+
+		var targets []*domain.Target
+		for i := range config.Configuration.Targets {
+			if selected[i] {
+				targets = append(targets, &config.Configuration.Targets[i])
+			}
+		}
+
+		if len(targets) == 0 {
+			cursor = index
+			continue
+		}
+
+		return targets, nil
+	}
+}
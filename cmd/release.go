@@ -17,10 +17,15 @@ limitations under the License.
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
 
+	"gotofu.com/mochi/change"
 	"gotofu.com/mochi/config"
 	"gotofu.com/mochi/domain"
 	"gotofu.com/mochi/release"
@@ -40,7 +45,8 @@ var releaseCmd = &cobra.Command{
 var releaseStartCmd = &cobra.Command{
 	Use:   "start [target]",
 	Short: "Start working on a release",
-	Long: `The "start" command creates a new branch following the format below:
+	Long: `The "start" command creates a new branch following the branch format of the
+target, by default:
 
 release/<target>/<version>`,
 	Args: cobra.ExactArgs(1),
@@ -96,7 +102,7 @@ release/<target>/<version>`,
 					Version: latestVersion,
 				}.String()
 			} else {
-				if baseVersion, err := version.Parse(baseFlag); err != nil {
+				if baseVersion, err := version.Parse(currentTarget, baseFlag); err != nil {
 					return err
 				} else {
 					gitBase = domain.Tag{
@@ -107,7 +113,23 @@ release/<target>/<version>`,
 			}
 		}
 
-		nextVersion := version.Next(currentTarget, latestVersion)
+		preLabel, _ := cmd.Flags().GetString("pre")
+		if preLabel != "" {
+			if err := domain.ValidatePreLabel(preLabel); err != nil {
+				return err
+			}
+		}
+
+		// The version following a pre-release is derived from it, so there is
+		// no need to determine the bump level.
+		bump := domain.BumpPatch
+		if latestVersion == nil || !latestVersion.IsPrerelease() {
+			if bump, err = getBumpLevel(cmd, currentTarget); err != nil {
+				return err
+			}
+		}
+
+		nextVersion := version.Next(currentTarget, latestVersion, bump, preLabel)
 
 		if err := git.Checkout(nextVersion.Branch(currentTarget), gitBase); err != nil {
 			return err
@@ -124,6 +146,87 @@ To finalize the release, run 'mochi release finish'. If you wish to preview the
 	},
 }
 
+var releaseHotfixCmd = &cobra.Command{
+	Use:   "hotfix [target] [tag]",
+	Short: "Start working on a hotfix for a past release",
+	Long: `The "hotfix" command creates a release branch from the tag of a past release,
+e.g. api@2024.38.0, and bumps the patch of its version, e.g. to 2024.38.1.
+
+Cherry-pick the fixes onto the branch, along with their release notes, and run
+'mochi release finish' to tag the hotfix and merge it back into the base branch.
+Pass --no-merge to finish if the base branch should be left untouched.`,
+	Args: cobra.ExactArgs(2),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch len(args) {
+		case 0:
+			return target.GetIds(), cobra.ShellCompDirectiveNoFileComp
+		case 1:
+			currentTarget, err := target.Get(args[0])
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			releases, err := release.List(currentTarget)
+			if err != nil {
+				return nil, cobra.ShellCompDirectiveError
+			}
+
+			var comps []string
+			for _, r := range releases {
+				comps = append(comps, r.Tag.String())
+			}
+
+			return comps, cobra.ShellCompDirectiveNoFileComp
+		default:
+			return cobra.AppendActiveHelp(nil, "ERROR: Too many arguments specified"), cobra.ShellCompDirectiveNoFileComp
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		currentTarget, err := target.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		if err := git.EnsureClean(); err != nil {
+			return err
+		}
+
+		// The release to fix may be given as a tag or as a version.
+		baseTag, err := tag.Parse(args[1])
+		if err != nil {
+			baseVersion, versionErr := version.Parse(currentTarget, args[1])
+			if versionErr != nil {
+				return err
+			}
+			baseTag = &domain.Tag{Target: currentTarget, Version: baseVersion}
+		}
+		if baseTag.Target.Id != currentTarget.Id {
+			return fmt.Errorf("tag %s is not a release of %s", baseTag.String(), currentTarget.Name)
+		}
+		if baseTag.Version.IsPrerelease() {
+			return fmt.Errorf("cannot start a hotfix for pre-release %s", baseTag.String())
+		}
+		if !git.RevisionExists(fmt.Sprintf("refs/tags/%s", baseTag.String())) {
+			return fmt.Errorf("tag %s does not exist", baseTag.String())
+		}
+
+		nextVersion := version.Hotfix(currentTarget, baseTag.Version)
+
+		if err := git.Checkout(nextVersion.Branch(currentTarget), baseTag.String()); err != nil {
+			return err
+		}
+
+		fmt.Printf(`Started hotfix %s for %s on branch %s, from %s.
+
+Cherry-pick the fixes for this hotfix, along with their release notes, e.g. with 'git cherry-pick <commit>'.
+
+To finalize the hotfix, run 'mochi release finish', with --no-merge to leave the base branch untouched.
+`, nextVersion.String(), currentTarget.Name, nextVersion.Branch(currentTarget), baseTag.String())
+
+		return nil
+	},
+}
+
 var releasePreviewCmd = &cobra.Command{
 	Use:   "preview",
 	Short: "Preview the release notes for an in-progress release",
@@ -141,20 +244,33 @@ var releasePreviewCmd = &cobra.Command{
 			return err
 		}
 
-		releaseNotes := release.Get(tag.Target)
-		if len(releaseNotes) == 0 {
+		index, err := change.LoadIndex()
+		if err != nil {
+			return err
+		}
+
+		if invalid := index.InvalidFor(tag.Target.Id); len(invalid) > 0 {
+			printDiagnostics(os.Stderr, invalid)
+		}
+
+		renderer, err := getRenderer(cmd)
+		if err != nil {
+			return err
+		}
+
+		releaseNotes := release.GetFromIndex(index, tag.Target)
+		if len(releaseNotes) == 0 && !renderer.Structured {
 			fmt.Println("No release notes found.")
 			return nil
 		}
 
 		rel := domain.Release{
 			Tag:   tag,
+			Date:  time.Now(),
 			Notes: releaseNotes,
 		}
-		fmt.Printf("Release notes for %s:\n", tag.String())
-		rel.Render(os.Stdout)
 
-		return nil
+		return printRelease(renderer, &rel)
 	},
 }
 
@@ -167,11 +283,6 @@ var releaseFinishCmd = &cobra.Command{
 2. Removes the release notes files, commits the changes, and tags the commit
 3. Merges the release branch into the base branch`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		rebase, err := cmd.Flags().GetBool("rebase")
-		if err != nil {
-			return err
-		}
-
 		currentBranch, err := git.CurrentBranch()
 		if err != nil {
 			return err
@@ -179,40 +290,423 @@ var releaseFinishCmd = &cobra.Command{
 		if currentBranch == config.Configuration.BaseBranch {
 			return fmt.Errorf("you must be on a release branch to finish a release")
 		}
+		if _, err := release.LoadTrain(); err == nil {
+			return fmt.Errorf("you are on a release train branch, run 'mochi release train finish' to finish it")
+		}
 
 		tag, err := tag.ParseFromBranch(currentBranch)
 		if err != nil {
 			return err
 		}
 
-		releaseNotes := release.Get(tag.Target)
-		if len(releaseNotes) == 0 {
-			return fmt.Errorf("no release notes found; add a release note to finish the release")
+		return finishRelease(cmd, tag)
+	},
+}
+
+var releasePromoteCmd = &cobra.Command{
+	Use:   "promote [target]",
+	Short: "Promote the latest pre-release to a final release",
+	Long: `The "promote" command turns the latest pre-release of a target into its final
+release, e.g. 2024.38.0-rc.2 into 2024.38.0. The final release is created from
+the pre-release tag and finished like with the "finish" command, so it ships
+the release notes accumulated over the pre-releases.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return cobra.AppendActiveHelp(nil, "This command does not take any more arguments (but may accept flags)"), cobra.ShellCompDirectiveNoFileComp
 		}
 
-		rel := domain.Release{
-			Tag:   tag,
-			Notes: releaseNotes,
+		return target.GetIds(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		currentTarget, err := target.Get(args[0])
+		if err != nil {
+			return err
 		}
-		fmt.Printf("Release notes for %s:\n", tag.String())
-		rel.Render(os.Stdout)
 
-		if err := release.Commit(&rel, rebase); err != nil {
+		if err := git.EnsureClean(); err != nil {
 			return err
 		}
 
-		return nil
+		if currentBranch, err := git.CurrentBranch(); err != nil {
+			return err
+		} else if currentBranch != config.Configuration.BaseBranch {
+			return fmt.Errorf("you must be on the base branch to promote a release")
+		}
+
+		latestVersion, err := version.Latest(currentTarget)
+		if err != nil {
+			return err
+		}
+		if !latestVersion.IsPrerelease() {
+			return fmt.Errorf("the latest release of %s, %s, is not a pre-release", currentTarget.Name, latestVersion)
+		}
+
+		preTag := domain.Tag{Target: currentTarget, Version: latestVersion}
+		finalTag := &domain.Tag{Target: currentTarget, Version: latestVersion.Final()}
+
+		if err := git.Checkout(finalTag.Branch(), preTag.String()); err != nil {
+			return err
+		}
+
+		fmt.Printf("Promoting %s to %s.\n", preTag.String(), finalTag.String())
+
+		return finishRelease(cmd, finalTag)
+	},
+}
+
+func finishRelease(cmd *cobra.Command, tag *domain.Tag) error {
+	// A release older than the latest one was branched from a past release by
+	// the hotfix command.
+	latestVersion, err := version.Latest(tag.Target)
+	hotfix := err == nil && tag.Version.Compare(latestVersion) < 0
+
+	options, err := getCommitOptions(cmd, hotfix)
+	if err != nil {
+		return err
+	}
+
+	renderer, err := getRenderer(cmd)
+	if err != nil {
+		return err
+	}
+
+	index, err := change.LoadIndex()
+	if err != nil {
+		return err
+	}
+
+	if invalid := index.InvalidFor(tag.Target.Id); len(invalid) > 0 {
+		printDiagnostics(os.Stderr, invalid)
+		if allowInvalid, _ := cmd.Flags().GetBool("allow-invalid"); !allowInvalid {
+			return fmt.Errorf("%d invalid release notes found; fix them or pass --allow-invalid to skip them", len(invalid))
+		}
+	}
+
+	releaseNotes := release.GetFromIndex(index, tag.Target)
+	if len(releaseNotes) == 0 {
+		return fmt.Errorf("no release notes found; add a release note to finish the release")
+	}
+
+	rel := domain.Release{
+		Tag:   tag,
+		Date:  time.Now(),
+		Notes: releaseNotes,
+	}
+	if err := printRelease(renderer, &rel); err != nil {
+		return err
+	}
+
+	if err := release.Commit(&rel, options); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var releaseListCmd = &cobra.Command{
+	Use:   "list [target]",
+	Short: "List the past releases",
+	Args:  cobra.MaximumNArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return cobra.AppendActiveHelp(nil, "This command does not take any more arguments (but may accept flags)"), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return target.GetIds(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var targets []*domain.Target
+
+		output, _ := cmd.Flags().GetString("output")
+		if output != "text" && output != "json" {
+			return fmt.Errorf("unsupported output %s", output)
+		}
+
+		if len(args) > 0 {
+			t, err := target.Get(args[0])
+			if err != nil {
+				return err
+			}
+			targets = append(targets, t)
+		} else {
+			for i := range config.Configuration.Targets {
+				targets = append(targets, &config.Configuration.Targets[i])
+			}
+		}
+
+		type releaseListEntry struct {
+			Tag     string    `json:"tag"`
+			Target  string    `json:"target"`
+			Version string    `json:"version"`
+			Date    time.Time `json:"date"`
+			Commit  string    `json:"commit"`
+		}
+
+		entries := []releaseListEntry{}
+		for _, t := range targets {
+			releases, err := release.List(t)
+			if err != nil {
+				return err
+			}
+
+			for _, r := range releases {
+				entries = append(entries, releaseListEntry{
+					Tag:     r.Tag.String(),
+					Target:  r.Tag.Target.Id,
+					Version: r.Tag.Version.String(),
+					Date:    r.Date,
+					Commit:  r.Commit,
+				})
+			}
+		}
+
+		if output == "json" {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			return encoder.Encode(entries)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No releases found.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TAG\tTARGET\tVERSION\tDATE\tCOMMIT")
+		for _, e := range entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", e.Tag, e.Target, e.Version, e.Date.Format("2006-01-02"), e.Commit)
+		}
+
+		return w.Flush()
+	},
+}
+
+var releaseShowCmd = &cobra.Command{
+	Use:   "show <tag>",
+	Short: "Show the release notes of a past release",
+	Long: `The "show" command prints the release notes of a past release, as stored in the
+annotation of its tag when the release was finished.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var comps []string
+
+		if len(args) > 0 {
+			return cobra.AppendActiveHelp(comps, "This command does not take any more arguments (but may accept flags)"), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		for i := range config.Configuration.Targets {
+			releases, _ := release.List(&config.Configuration.Targets[i])
+			for _, r := range releases {
+				comps = append(comps, r.Tag.String())
+			}
+		}
+
+		return comps, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		renderer, err := getRenderer(cmd)
+		if err != nil {
+			return err
+		}
+
+		rel, err := release.Show(args[0])
+		if err != nil {
+			return err
+		}
+
+		return printRelease(renderer, rel)
+	},
+}
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "notes <target>",
+	Short: "Show the combined release notes of several past releases",
+	Long: `The "notes" command merges the release notes of every release of a target
+after the --from version, up to and including the --to version, into a single
+set of release notes.
+
+Without --from, the range starts at the first release. Without --to, it ends
+at the latest release.
+
+Only the releases finished with a version of mochi storing the release notes in
+their tag are covered; older releases in the range are skipped with a warning.`,
+	Args: cobra.ExactArgs(1),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return cobra.AppendActiveHelp(nil, "This command does not take any more arguments (but may accept flags)"), cobra.ShellCompDirectiveNoFileComp
+		}
+
+		return target.GetIds(), cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var (
+			from, to *domain.Version
+			err      error
+		)
+
+		renderer, err := getRenderer(cmd)
+		if err != nil {
+			return err
+		}
+
+		currentTarget, err := target.Get(args[0])
+		if err != nil {
+			return err
+		}
+
+		if fromFlag, _ := cmd.Flags().GetString("from"); fromFlag != "" {
+			if from, err = version.Parse(currentTarget, fromFlag); err != nil {
+				return err
+			}
+		}
+		if toFlag, _ := cmd.Flags().GetString("to"); toFlag != "" {
+			if to, err = version.Parse(currentTarget, toFlag); err != nil {
+				return err
+			}
+		}
+
+		rel, skipped, err := release.Cumulative(currentTarget, from, to)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range skipped {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s, its tag does not hold its release notes; pass --from to leave it out of the range.\n", r.Tag.String())
+		}
+
+		return printRelease(renderer, rel)
 	},
 }
 
+func getBumpLevel(cmd *cobra.Command, t *domain.Target) (domain.BumpLevel, error) {
+	bumpFlag, _ := cmd.Flags().GetString("bump")
+	if !t.Scheme().UsesBumpLevel() {
+		if bumpFlag != "" {
+			versioning := t.Versioning
+			if versioning == "" {
+				versioning = domain.DefaultVersioningScheme
+			}
+			return domain.BumpPatch, fmt.Errorf("--bump cannot be used for %s, which uses the %s versioning scheme", t.Name, versioning)
+		}
+
+		return domain.BumpPatch, nil
+	}
+
+	if bumpFlag != "" {
+		return domain.ParseBumpLevel(bumpFlag)
+	}
+
+	index, err := change.LoadIndex()
+	if err != nil {
+		return domain.BumpPatch, err
+	}
+
+	if invalid := index.InvalidFor(t.Id); len(invalid) > 0 {
+		printDiagnostics(os.Stderr, invalid)
+	}
+
+	releaseNotes := release.GetFromIndex(index, t)
+	if len(releaseNotes) == 0 {
+		return domain.BumpPatch, fmt.Errorf("no release notes found to infer the version bump from; add a release note or pass --bump")
+	}
+
+	level, drivers := release.InferBumpLevel(releaseNotes)
+
+	fmt.Printf("Bumping the %s version of %s because of:\n", level, t.Name)
+	for _, d := range drivers {
+		reason := d.Change.Type.Name
+		if d.Change.Breaking {
+			reason = "breaking change"
+		}
+		fmt.Printf("  - %s (%s)\n", d.File, reason)
+	}
+	fmt.Println()
+
+	return level, nil
+}
+
+func getRenderer(cmd *cobra.Command) (release.Renderer, error) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return release.Renderer{}, err
+	}
+
+	return release.GetRenderer(format)
+}
+
+func printRelease(renderer release.Renderer, rel *domain.Release) error {
+	if !renderer.Structured {
+		fmt.Printf("Release notes for %s:\n", rel.Tag.String())
+	}
+
+	return renderer.Render(rel, os.Stdout)
+}
+
+func getCommitOptions(cmd *cobra.Command, hotfix bool) (release.CommitOptions, error) {
+	var (
+		options = release.CommitOptions{Hotfix: hotfix}
+		err     error
+	)
+	if options.Rebase, err = cmd.Flags().GetBool("rebase"); err != nil {
+		return options, err
+	}
+	if options.SkipMerge, err = cmd.Flags().GetBool("no-merge"); err != nil {
+		return options, err
+	}
+	if options.Rebase {
+		options.Diverged = !git.IsAncestor(config.Configuration.BaseBranch, "HEAD")
+	}
+	options.Push = config.Configuration.Push
+	if cmd.Flags().Changed("push") {
+		if options.Push, err = cmd.Flags().GetBool("push"); err != nil {
+			return options, err
+		}
+	}
+
+	return options, options.Validate()
+}
+
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("format", "f", release.DefaultFormat, fmt.Sprintf("the format of the release notes (%s)", strings.Join(release.Formats(), ", ")))
+	cmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(release.Formats(), cobra.ShellCompDirectiveNoFileComp))
+}
+
+func addFinishFlags(cmd *cobra.Command) {
+	addFormatFlag(cmd)
+
+	cmd.Flags().Bool("rebase", false, "rebase the release branch on top of the base branch instead of merging it")
+	cmd.Flags().Bool("no-merge", false, "leave the base branch untouched instead of merging the release branch into it, e.g. for hotfixes")
+	cmd.Flags().Bool("push", false, "push the base branch and the tag to the remote, atomically (defaults to the push setting)")
+	cmd.Flags().Bool("allow-invalid", false, "finish the release even if some release notes are invalid, skipping them")
+}
+
 func init() {
-	releaseStartCmd.Flags().StringP("base", "b", "", "the base version to start the release from (e.g. 2024.1.0, latest)")
+	addFormatFlag(releasePreviewCmd)
+	addFinishFlags(releaseFinishCmd)
+	addFinishFlags(releasePromoteCmd)
+	addFormatFlag(releaseShowCmd)
+	addFormatFlag(releaseNotesCmd)
 
-	releaseFinishCmd.Flags().Bool("rebase", false, "rebase the release branch on top of the base branch instead of merging it")
+	releaseNotesCmd.Flags().String("from", "", "the version the range starts after (e.g. 2024.30.0)")
+	releaseNotesCmd.Flags().String("to", "", "the last version included in the range (e.g. 2024.38.1)")
+
+	releaseListCmd.Flags().StringP("output", "o", "text", "the output format (text, json)")
+	releaseListCmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+
+	releaseStartCmd.Flags().StringP("base", "b", "", "the base version to start the release from (e.g. 2024.1.0, latest)")
+	releaseStartCmd.Flags().String("pre", "", "start a pre-release with the given label (e.g. rc, beta)")
+	releaseStartCmd.Flags().String("bump", "", "the version to bump for targets using semantic versioning (major, minor, patch), inferred from the release notes by default")
+	releaseStartCmd.RegisterFlagCompletionFunc("bump", cobra.FixedCompletions([]string{"major", "minor", "patch"}, cobra.ShellCompDirectiveNoFileComp))
 
 	releaseCmd.AddCommand(releaseStartCmd)
+	releaseCmd.AddCommand(releaseHotfixCmd)
 	releaseCmd.AddCommand(releasePreviewCmd)
 	releaseCmd.AddCommand(releaseFinishCmd)
+	releaseCmd.AddCommand(releasePromoteCmd)
+	releaseCmd.AddCommand(releaseListCmd)
+	releaseCmd.AddCommand(releaseShowCmd)
+	releaseCmd.AddCommand(releaseNotesCmd)
 
 	rootCmd.AddCommand(releaseCmd)
 }
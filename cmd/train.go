@@ -0,0 +1,252 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gotofu.com/mochi/change"
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/release"
+	"gotofu.com/mochi/target"
+	"gotofu.com/mochi/utils/git"
+	"gotofu.com/mochi/version"
+
+	"github.com/spf13/cobra"
+)
+
+var releaseTrainCmd = &cobra.Command{
+	Use:   "train [command]",
+	Short: "Release several targets together",
+}
+
+var releaseTrainStartCmd = &cobra.Command{
+	Use:   "start [target...]",
+	Short: "Start working on a release of several targets",
+	Long: `The "start" command creates a single branch to release several targets
+together, following the train branch format, by default:
+
+release/train/<date>
+
+The targets and versions of the train are committed to .mochi/train.yaml on the
+branch, so the train can be finished from any clone. Finishing it with
+'mochi release train finish' creates one release commit and one tag per target.`,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var comps []string
+		for _, id := range target.GetIds() {
+			if !slices.Contains(args, id) {
+				comps = append(comps, id)
+			}
+		}
+
+		return comps, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		allWithChanges, _ := cmd.Flags().GetBool("all-with-changes")
+		if allWithChanges && len(args) > 0 {
+			return fmt.Errorf("targets cannot be given along with --all-with-changes")
+		} else if !allWithChanges && len(args) == 0 {
+			return fmt.Errorf("give the targets to release or pass --all-with-changes")
+		}
+
+		if err := git.EnsureClean(); err != nil {
+			return err
+		}
+
+		if currentBranch, err := git.CurrentBranch(); err != nil {
+			return err
+		} else if currentBranch != config.Configuration.BaseBranch {
+			return fmt.Errorf("you must be on the base branch to start a release train")
+		}
+
+		index, err := change.LoadIndex()
+		if err != nil {
+			return err
+		}
+
+		var targets []*domain.Target
+		if allWithChanges {
+			for i, t := range config.Configuration.Targets {
+				if len(release.GetFromIndex(index, &t)) > 0 {
+					targets = append(targets, &config.Configuration.Targets[i])
+				}
+			}
+			if len(targets) == 0 {
+				return fmt.Errorf("no target has release notes to release")
+			}
+		} else if targets, err = target.GetAll(args); err != nil {
+			return err
+		}
+
+		var tags []*domain.Tag
+		for _, t := range targets {
+			if len(release.GetFromIndex(index, t)) == 0 {
+				return fmt.Errorf("no release notes found for %s; add a release note or leave it out of the train", t.Name)
+			}
+
+			latestVersion, err := version.Latest(t)
+			if err != nil {
+				slog.Debug("No valid version found in git tags, falling back to the default current version.", "target", t.Id, "error", err.Error())
+			}
+
+			bump := domain.BumpPatch
+			if latestVersion == nil || !latestVersion.IsPrerelease() {
+				if bump, err = getBumpLevel(cmd, t); err != nil {
+					return err
+				}
+			}
+
+			tags = append(tags, &domain.Tag{
+				Target:  t,
+				Version: version.Next(t, latestVersion, bump, ""),
+			})
+		}
+
+		branch, err := release.StartTrain(release.NextTrainName(time.Now()), tags)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Started release train on branch %s with:\n", branch)
+		for _, t := range tags {
+			fmt.Printf("  - %s %s\n", t.Target.Name, t.Version.String())
+		}
+		fmt.Printf(`
+You can add additional commits in preparation for these releases if you wish.
+
+To finalize the releases, run 'mochi release train finish'.
+`)
+
+		return nil
+	},
+}
+
+var releaseTrainFinishCmd = &cobra.Command{
+	Use:   "finish",
+	Short: "Finish the release train on the current branch",
+	Long: `The "finish" command removes the release notes of all the targets of the train
+in a single commit, and tags the release of each target. If any of them cannot
+be released, nothing is tagged.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		currentBranch, err := git.CurrentBranch()
+		if err != nil {
+			return err
+		}
+
+		tags, err := release.LoadTrain()
+		if err != nil {
+			return err
+		}
+
+		options, err := getCommitOptions(cmd, false)
+		if err != nil {
+			return err
+		}
+
+		renderer, err := getRenderer(cmd)
+		if err != nil {
+			return err
+		}
+
+		index, err := change.LoadIndex()
+		if err != nil {
+			return err
+		}
+
+		var (
+			invalid  []*change.InvalidChange
+			problems []string
+			releases []*domain.Release
+			now      = time.Now()
+		)
+		for _, t := range tags {
+			for _, c := range index.InvalidFor(t.Target.Id) {
+				if !slices.Contains(invalid, c) {
+					invalid = append(invalid, c)
+				}
+			}
+
+			releaseNotes := release.GetFromIndex(index, t.Target)
+			if len(releaseNotes) == 0 {
+				problems = append(problems, fmt.Sprintf("no release notes found for %s", t.Target.Name))
+			}
+
+			releases = append(releases, &domain.Release{
+				Tag:   t,
+				Date:  now,
+				Notes: releaseNotes,
+			})
+		}
+
+		if len(invalid) > 0 {
+			printDiagnostics(os.Stderr, invalid)
+			if allowInvalid, _ := cmd.Flags().GetBool("allow-invalid"); !allowInvalid {
+				problems = append(problems, fmt.Sprintf("%d invalid release notes found; fix them or pass --allow-invalid to skip them", len(invalid)))
+			}
+		}
+
+		if len(problems) > 0 {
+			return fmt.Errorf("cannot finish the release train, nothing was tagged: %s", strings.Join(problems, "; "))
+		}
+
+		if renderer.Structured {
+			for _, rel := range releases {
+				if err := renderer.Render(rel, os.Stdout); err != nil {
+					return err
+				}
+			}
+		} else {
+			fmt.Printf("Release train %s:\n\n", currentBranch)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "TAG\tTARGET\tVERSION\tCHANGES")
+			for _, rel := range releases {
+				changes := 0
+				for _, note := range rel.Notes {
+					changes += len(note.Changes)
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", rel.Tag.String(), rel.Tag.Target.Id, rel.Tag.Version.String(), changes)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+
+		return release.CommitTrain(releases, currentBranch, options)
+	},
+}
+
+func init() {
+	releaseTrainStartCmd.Flags().Bool("all-with-changes", false, "release all the targets with release notes")
+	releaseTrainStartCmd.Flags().String("bump", "", "the version to bump for targets using semantic versioning (major, minor, patch), inferred from the release notes by default")
+	releaseTrainStartCmd.RegisterFlagCompletionFunc("bump", cobra.FixedCompletions([]string{"major", "minor", "patch"}, cobra.ShellCompDirectiveNoFileComp))
+
+	addFinishFlags(releaseTrainFinishCmd)
+
+	releaseTrainCmd.AddCommand(releaseTrainStartCmd)
+	releaseTrainCmd.AddCommand(releaseTrainFinishCmd)
+
+	releaseCmd.AddCommand(releaseTrainCmd)
+}
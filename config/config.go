@@ -19,33 +19,113 @@ package config
 import (
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
 
 	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/utils/git"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
+type Links struct {
+	Issue       string
+	PullRequest string
+}
+
+// TagDiscovery controls which tags are considered to find the latest version
+// of a target.
+type TagDiscovery struct {
+	// ReachableOnly ignores the tags which are not reachable from the base
+	// branch.
+	ReachableOnly bool
+	// IncludeRemote also considers the tags on the remote which were not
+	// fetched.
+	IncludeRemote bool
+}
+
 type Config struct {
 	Types      []domain.ChangeType
 	Targets    []domain.Target
 	BaseBranch string
+	SkipMarker string
+	Edit       bool
+	Links      Links
+	// ChangelogMarker is the line after which new releases are inserted in
+	// changelog files. Releases are prepended to the file if it is missing.
+	ChangelogMarker string
+	// TagFormat and BranchFormat are the defaults for the targets which do
+	// not configure their own.
+	TagFormat    string
+	BranchFormat string
+	// TrainBranchFormat names the release train branches, with "train" as
+	// the target. It defaults to the branch format.
+	TrainBranchFormat string
+	// Remote is the remote tags are discovered on and releases are pushed to.
+	Remote       string
+	TagDiscovery TagDiscovery
+	// Push pushes the base branch and the tags when finishing releases.
+	Push bool
 }
 
 var Configuration *Config
 
+// WorkingDirectory is the directory mochi was started from, relative to the
+// project directory it switches to on startup.
+var WorkingDirectory = "."
+
+// projectDirectory returns the nearest directory containing a .mochi
+// directory, from the working directory up to the repository root, so that
+// projects nested in a larger repository are supported. It defaults to the
+// repository root.
+func projectDirectory(root string, wd string) string {
+	for dir := wd; ; dir = filepath.Dir(dir) {
+		if info, err := os.Stat(filepath.Join(dir, ".mochi")); err == nil && info.IsDir() {
+			return dir
+		}
+		if dir == root || dir == filepath.Dir(dir) {
+			return root
+		}
+	}
+}
+
 func InitConfig() {
+	if root, err := git.RootDirectory(); err != nil {
+		slog.Debug("Not in a git repository, using the current directory as root.", "error", err)
+	} else if wd, err := os.Getwd(); err != nil {
+		cobra.CheckErr(err)
+	} else {
+		if wd, err = filepath.EvalSymlinks(wd); err != nil {
+			cobra.CheckErr(err)
+		}
+		project := projectDirectory(root, wd)
+		slog.Debug("Project directory found.", "directory", project)
+
+		if WorkingDirectory, err = filepath.Rel(project, wd); err != nil {
+			cobra.CheckErr(err)
+		}
+		WorkingDirectory = filepath.ToSlash(WorkingDirectory)
+		cobra.CheckErr(os.Chdir(project))
+	}
+
 	viper.AddConfigPath("./.mochi")
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
 
 	viper.SetDefault("baseBranch", "main")
+	viper.SetDefault("skipMarker", "skip-changelog")
+	viper.SetDefault("changelogMarker", "<!-- mochi -->")
+	viper.SetDefault("remote", "origin")
+	viper.SetDefault("tagFormat", domain.DefaultTagFormat)
+	viper.SetDefault("branchFormat", domain.DefaultBranchFormat)
 	viper.SetDefault("types", []domain.ChangeType{
-		{Id: "feature", Name: "Feature", Title: "Features"},
-		{Id: "bugfix", Name: "Bug fix", Title: "Bug Fixes"},
-		{Id: "doc", Name: "Documentation", Title: "Documentation"},
-		{Id: "removal", Name: "Removal", Title: "Removals"},
-		{Id: "misc", Name: "Miscellaneous", Title: "Miscellaneous"},
+		{Id: "feature", Name: "Feature", Title: "Features", Bump: "minor"},
+		{Id: "bugfix", Name: "Bug fix", Title: "Bug Fixes", Bump: "patch"},
+		{Id: "doc", Name: "Documentation", Title: "Documentation", Bump: "patch"},
+		{Id: "removal", Name: "Removal", Title: "Removals", Bump: "major"},
+		{Id: "misc", Name: "Miscellaneous", Title: "Miscellaneous", Bump: "patch"},
 	})
 	viper.SetDefault("targets", []domain.Target{})
 
@@ -58,4 +138,72 @@ func InitConfig() {
 	if err := viper.Unmarshal(&Configuration); err != nil {
 		cobra.CheckErr(fmt.Errorf("unable to decode into struct, %v", err))
 	}
+
+	for _, t := range Configuration.Types {
+		if t.Bump == "" {
+			continue
+		}
+		if _, err := domain.ParseBumpLevel(t.Bump); err != nil {
+			cobra.CheckErr(fmt.Errorf("invalid configuration for type %s: %w", t.Id, err))
+		}
+	}
+
+	if Configuration.TrainBranchFormat == "" {
+		Configuration.TrainBranchFormat = Configuration.BranchFormat
+	}
+
+	for _, format := range []string{Configuration.TagFormat, Configuration.BranchFormat, Configuration.TrainBranchFormat} {
+		if err := domain.ValidateNameFormat(format); err != nil {
+			cobra.CheckErr(fmt.Errorf("invalid configuration: %w", err))
+		}
+	}
+
+	tagFormats := map[string]string{}
+	branchFormats := map[string]string{}
+	for i := range Configuration.Targets {
+		t := &Configuration.Targets[i]
+		if t.TagFormat == "" {
+			t.TagFormat = Configuration.TagFormat
+		}
+		if t.BranchFormat == "" {
+			t.BranchFormat = Configuration.BranchFormat
+		}
+
+		for _, format := range []string{t.TagFormat, t.BranchFormat} {
+			if err := domain.ValidateNameFormat(format); err != nil {
+				cobra.CheckErr(fmt.Errorf("invalid configuration for target %s: %w", t.Id, err))
+			}
+		}
+		// Names of different targets must differ to be parsed back.
+		if other, ok := tagFormats[t.TagName(domain.Version{})]; ok {
+			cobra.CheckErr(fmt.Errorf("invalid configuration for target %s: tag format conflicts with target %s", t.Id, other))
+		}
+		tagFormats[t.TagName(domain.Version{})] = t.Id
+		if other, ok := branchFormats[t.BranchName(domain.Version{})]; ok {
+			cobra.CheckErr(fmt.Errorf("invalid configuration for target %s: branch format conflicts with target %s", t.Id, other))
+		}
+		branchFormats[t.BranchName(domain.Version{})] = t.Id
+		if _, ok := t.ParseBranchName(domain.TrainBranchName(Configuration.TrainBranchFormat, "name")); ok {
+			cobra.CheckErr(fmt.Errorf("invalid configuration for target %s: branch format conflicts with release train branches, set trainBranchFormat", t.Id))
+		}
+
+		if _, err := domain.GetVersioningScheme(t.Versioning); err != nil {
+			cobra.CheckErr(fmt.Errorf("invalid configuration for target %s: %w", t.Id, err))
+		}
+		for _, f := range t.VersionFiles {
+			if _, err := f.Patterns(); err != nil {
+				cobra.CheckErr(fmt.Errorf("invalid configuration for target %s: %w", t.Id, err))
+			}
+		}
+	}
+}
+
+// ResolvePath resolves a path given relative to the directory mochi was
+// started from.
+func ResolvePath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+
+	return filepath.Join(WorkingDirectory, path)
 }
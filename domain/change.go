@@ -17,29 +17,172 @@ limitations under the License.
 package domain
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 	"text/template"
 	"time"
 )
 
-var changeTemplate, _ = template.New("change").Parse(
+var changeTemplate, _ = template.New("change").Funcs(template.FuncMap{
+	"quote": func(s string) string {
+		quoted, _ := json.Marshal(s)
+		return string(quoted)
+	},
+}).Parse(
 	`---
-target: {{ .Target.Id }}
+targets:
+{{- range .Targets }}
+  - {{ .Id }}
+{{- end }}
 type: {{ .Type.Id }}
+{{- if .Breaking }}
+breaking: true
+{{- end }}
+{{- with .Issues }}
+issues:
+{{- range . }}
+  - {{ quote . }}
+{{- end }}
+{{- end }}
+{{- with .PR }}
+pr: {{ quote . }}
+{{- end }}
+{{- with .Authors }}
+authors:
+{{- range . }}
+  - {{ quote . }}
+{{- end }}
+{{- end }}
+{{- with .Metadata }}
+metadata:
+{{- range $key, $value := . }}
+  {{ quote $key }}: {{ quote $value }}
+{{- end }}
+{{- end }}
 ---
 
 {{ .Message }}
 `)
 
 type Change struct {
-	Type    *ChangeType
-	Target  *Target
-	Message string
+	Type     *ChangeType
+	Targets  []*Target
+	Message  string
+	Metadata map[string]string
+	Issues   []string
+	PR       string
+	Authors  []string
+	Breaking bool
 }
 
+func (c Change) BumpLevel() BumpLevel {
+	if c.Breaking {
+		return BumpMajor
+	}
+
+	return c.Type.BumpLevel()
+}
+
+func (c Change) TargetIds() []string {
+	var ids []string
+	for _, t := range c.Targets {
+		ids = append(ids, t.Id)
+	}
+
+	return ids
+}
+
+func (c Change) HasTarget(id string) bool {
+	for _, t := range c.Targets {
+		if t.Id == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Change) RemoveTarget(id string) {
+	targets := []*Target{}
+	for _, t := range c.Targets {
+		if t.Id != id {
+			targets = append(targets, t)
+		}
+	}
+
+	c.Targets = targets
+}
+
+// References returns the issues, pull request and authors of the change,
+// formatted for display, e.g. "#123" and "@alice".
+func (c Change) References() []string {
+	var references []string
+	for _, issue := range append(slices.Clone(c.Issues), c.PR) {
+		if issue == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(issue); err == nil {
+			issue = "#" + issue
+		}
+		if !slices.Contains(references, issue) {
+			references = append(references, issue)
+		}
+	}
+	for _, author := range c.Authors {
+		if !strings.ContainsAny(author, " @") {
+			author = "@" + author
+		}
+		references = append(references, author)
+	}
+
+	return references
+}
+
+func (c Change) Summary() string {
+	summary, _, _ := strings.Cut(c.Message, "\n")
+	return strings.TrimSpace(summary)
+}
+
+var slugRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+const maxSlugLength = 40
+
+func (c Change) Slug() string {
+	slug := strings.Trim(slugRegex.ReplaceAllString(strings.ToLower(c.Summary()), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if i := strings.LastIndex(slug, "-"); i > 0 {
+			slug = slug[:i]
+		}
+	}
+
+	return strings.Trim(slug, "-")
+}
+
+// Filename returns a unique file name for the change, named after its first
+// target only, so that changes to many targets keep a short name.
 func (c Change) Filename() string {
-	return fmt.Sprintf("%s-%s-%s.md", time.Now().Format("20060102150405"), c.Target.Id, c.Type.Id)
+	parts := []string{time.Now().Format("20060102150405")}
+	if len(c.Targets) > 0 {
+		parts = append(parts, c.Targets[0].Id)
+	}
+	parts = append(parts, c.Type.Id)
+	if slug := c.Slug(); slug != "" {
+		parts = append(parts, slug)
+	}
+
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	parts = append(parts, hex.EncodeToString(suffix))
+
+	return fmt.Sprintf("%s.md", strings.Join(parts, "-"))
 }
 
 func (c Change) Render(wr io.Writer) error {
@@ -0,0 +1,54 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestChangeFilename(t *testing.T) {
+	tests := []struct {
+		name   string
+		change Change
+		want   string
+	}{
+		{
+			name:   "single target",
+			change: Change{Targets: []*Target{{Id: "api"}}, Type: &ChangeType{Id: "bugfix"}, Message: "Fix the login"},
+			want:   `^\d{14}-api-bugfix-fix-the-login-[0-9a-f]{8}\.md$`,
+		},
+		{
+			name:   "several targets",
+			change: Change{Targets: []*Target{{Id: "web"}, {Id: "api"}, {Id: "web-admin"}}, Type: &ChangeType{Id: "feature"}, Message: "Add dark mode"},
+			want:   `^\d{14}-web-feature-add-dark-mode-[0-9a-f]{8}\.md$`,
+		},
+		{
+			name:   "no slug",
+			change: Change{Targets: []*Target{{Id: "api"}}, Type: &ChangeType{Id: "misc"}, Message: "!!!"},
+			want:   `^\d{14}-api-misc-[0-9a-f]{8}\.md$`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.Filename(); !regexp.MustCompile(tt.want).MatchString(got) {
+				t.Errorf("Filename() = %q, want a match for %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -20,4 +20,15 @@ type ChangeType struct {
 	Id    string
 	Name  string
 	Title string
+	// Bump is the level of the version bump required by changes of this type
+	// for targets using semantic versioning, defaulting to patch.
+	Bump string
+}
+
+func (t ChangeType) BumpLevel() BumpLevel {
+	if level, err := ParseBumpLevel(t.Bump); err == nil {
+		return level
+	}
+
+	return BumpPatch
 }
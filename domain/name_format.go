@@ -0,0 +1,113 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	DefaultTagFormat    = "{target}@{version}"
+	DefaultBranchFormat = "release/{target}/{version}"
+)
+
+const (
+	targetPlaceholder  = "{target}"
+	versionPlaceholder = "{version}"
+)
+
+// ValidateNameFormat checks that a tag or branch format contains the version
+// placeholder exactly once, so that names can be parsed back into versions.
+func ValidateNameFormat(format string) error {
+	if n := strings.Count(format, versionPlaceholder); n != 1 {
+		return fmt.Errorf("format %q must contain %s exactly once", format, versionPlaceholder)
+	}
+
+	return nil
+}
+
+func formatName(format string, t *Target, v Version) string {
+	return strings.NewReplacer(targetPlaceholder, t.Id, versionPlaceholder, v.String()).Replace(format)
+}
+
+// parseName returns the raw version in a name following the format, which
+// is formatted for the given target.
+func parseName(format string, t *Target, name string) (string, bool) {
+	before, after, _ := strings.Cut(format, versionPlaceholder)
+	expand := func(s string) string {
+		return regexp.QuoteMeta(strings.ReplaceAll(s, targetPlaceholder, t.Id))
+	}
+
+	matches := regexp.MustCompile("^" + expand(before) + "(.+)" + expand(after) + "$").FindStringSubmatch(name)
+	if matches == nil {
+		return "", false
+	}
+
+	return matches[1], true
+}
+
+// TrainTarget replaces the target placeholder in the names of release train
+// branches.
+const TrainTarget = "train"
+
+// TrainBranchName returns the branch of the release train with the given
+// name, which replaces the version placeholder.
+func TrainBranchName(format string, name string) string {
+	return strings.NewReplacer(targetPlaceholder, TrainTarget, versionPlaceholder, name).Replace(format)
+}
+
+func (t Target) tagFormat() string {
+	if t.TagFormat != "" {
+		return t.TagFormat
+	}
+
+	return DefaultTagFormat
+}
+
+func (t Target) branchFormat() string {
+	if t.BranchFormat != "" {
+		return t.BranchFormat
+	}
+
+	return DefaultBranchFormat
+}
+
+func (t Target) TagName(v Version) string {
+	return formatName(t.tagFormat(), &t, v)
+}
+
+func (t Target) BranchName(v Version) string {
+	return formatName(t.branchFormat(), &t, v)
+}
+
+// ParseTagName returns the raw version of a tag of the target.
+func (t Target) ParseTagName(name string) (string, bool) {
+	return parseName(t.tagFormat(), &t, name)
+}
+
+// ParseBranchName returns the raw version of a release branch of the target.
+func (t Target) ParseBranchName(name string) (string, bool) {
+	return parseName(t.branchFormat(), &t, name)
+}
+
+// TagPattern returns a glob pattern matching the tags of the target, to
+// narrow down the tags listed from git.
+func (t Target) TagPattern() string {
+	return strings.NewReplacer(targetPlaceholder, t.Id, versionPlaceholder, "*").Replace(t.tagFormat())
+}
@@ -0,0 +1,130 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import "testing"
+
+func TestNameFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     Target
+		version    Version
+		wantTag    string
+		wantBranch string
+	}{
+		{
+			name:       "calver-week with default formats",
+			target:     Target{Id: "api"},
+			version:    Version{Major: 2024, Minor: 38, Patch: 1},
+			wantTag:    "api@2024.38.1",
+			wantBranch: "release/api/2024.38.1",
+		},
+		{
+			name:       "calver-month with a pre-release",
+			target:     Target{Id: "api", Versioning: "calver-month"},
+			version:    Version{Major: 2024, Minor: 9, Patch: 0, PreLabel: "rc", PreNumber: 2},
+			wantTag:    "api@2024.9.0-rc.2",
+			wantBranch: "release/api/2024.9.0-rc.2",
+		},
+		{
+			name:       "semver with a v prefix",
+			target:     Target{Id: "web", Versioning: "semver", TagFormat: "{target}/v{version}", BranchFormat: "release/{target}/v{version}"},
+			version:    Version{Major: 1, Minor: 2, Patch: 3},
+			wantTag:    "web/v1.2.3",
+			wantBranch: "release/web/v1.2.3",
+		},
+		{
+			name:       "semver with a v prefix and a pre-release",
+			target:     Target{Id: "web", Versioning: "semver", TagFormat: "{target}/v{version}"},
+			version:    Version{Major: 2, Minor: 0, Patch: 0, PreLabel: "beta", PreNumber: 1},
+			wantTag:    "web/v2.0.0-beta.1",
+			wantBranch: "release/web/2.0.0-beta.1",
+		},
+		{
+			name:       "hyphenated target with a hyphenated format",
+			target:     Target{Id: "web-admin", TagFormat: "{target}-{version}", BranchFormat: "{target}-release-{version}"},
+			version:    Version{Major: 2024, Minor: 38, Patch: 0, PreLabel: "rc", PreNumber: 1},
+			wantTag:    "web-admin-2024.38.0-rc.1",
+			wantBranch: "web-admin-release-2024.38.0-rc.1",
+		},
+		{
+			name:       "format without target",
+			target:     Target{Id: "cli", Versioning: "semver", TagFormat: "v{version}"},
+			version:    Version{Major: 0, Minor: 1, Patch: 0},
+			wantTag:    "v0.1.0",
+			wantBranch: "release/cli/0.1.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.target.TagName(tt.version); got != tt.wantTag {
+				t.Errorf("TagName() = %q, want %q", got, tt.wantTag)
+			}
+			if got := tt.target.BranchName(tt.version); got != tt.wantBranch {
+				t.Errorf("BranchName() = %q, want %q", got, tt.wantBranch)
+			}
+
+			for kind, parse := range map[string]func(string) (string, bool){
+				tt.wantTag:    tt.target.ParseTagName,
+				tt.wantBranch: tt.target.ParseBranchName,
+			} {
+				raw, ok := parse(kind)
+				if !ok {
+					t.Fatalf("%q does not match the format", kind)
+				}
+
+				v, err := tt.target.Scheme().Parse(raw)
+				if err != nil {
+					t.Fatalf("could not parse version %q of %q: %v", raw, kind, err)
+				}
+				if v.Compare(&tt.version) != 0 || v.String() != tt.version.String() {
+					t.Errorf("%q parsed as %s, want %s", kind, v, tt.version.String())
+				}
+			}
+		})
+	}
+}
+
+func TestNameFormatOverlappingTargets(t *testing.T) {
+	web := Target{Id: "web", TagFormat: "{target}-{version}"}
+	webAdmin := Target{Id: "web-admin", TagFormat: "{target}-{version}"}
+
+	name := webAdmin.TagName(Version{Major: 2024, Minor: 38, Patch: 0})
+
+	// The tag of web-admin matches the format of web, but its version does
+	// not parse, which is how tags are told apart.
+	raw, ok := web.ParseTagName(name)
+	if !ok {
+		t.Fatalf("%q does not match the format of web", name)
+	}
+	if _, err := web.Scheme().Parse(raw); err == nil {
+		t.Errorf("%q parsed as a version of web", name)
+	}
+
+	raw, ok = webAdmin.ParseTagName(name)
+	if !ok {
+		t.Fatalf("%q does not match the format of web-admin", name)
+	}
+	if _, err := webAdmin.Scheme().Parse(raw); err != nil {
+		t.Errorf("%q did not parse as a version of web-admin: %v", name, err)
+	}
+
+	if _, ok := webAdmin.ParseTagName(web.TagName(Version{Major: 2024, Minor: 38, Patch: 0})); ok {
+		t.Errorf("tag of web matches the format of web-admin")
+	}
+}
@@ -19,6 +19,7 @@ package domain
 import (
 	"io"
 	"text/template"
+	"time"
 )
 
 type ReleaseChange struct {
@@ -33,18 +34,41 @@ type ReleaseNote struct {
 
 type Release struct {
 	Tag   *Tag
+	Date  time.Time
 	Notes []*ReleaseNote
 }
 
-var releaseTemplate = template.Must(template.New("release").Parse(`
+var DefaultReleaseTemplate = template.Must(template.New("release").Funcs(TemplateFuncs).Parse(`
 {{- range .Notes }}
 ## {{ .Type.Title }}
 {{ range .Changes -}}
-- {{ .Change.Message }}
+- {{ .Change.Message | indent 2 | trimSpace }}{{ with .Change.References }} ({{ join . ", " }}){{ end }}
 {{ end -}}
 {{ end -}}
 `))
 
+var DefaultChangelogTemplate = template.Must(template.New("changelog").Funcs(TemplateFuncs).Parse(`
+## {{ .Version }} ({{ date "2006-01-02" .Date }})
+{{ range .Notes }}
+### {{ .Type.Title }}
+{{ range .Changes -}}
+- {{ .Change.Message | indent 2 | trimSpace }}{{ with .Change.References }} ({{ join . ", " }}){{ end }}
+{{ end -}}
+{{ end -}}
+`))
+
+func (r Release) Version() *Version {
+	return r.Tag.Version
+}
+
+func (r Release) Target() *Target {
+	return r.Tag.Target
+}
+
 func (r Release) Render(wr io.Writer) error {
-	return releaseTemplate.Execute(wr, r)
+	return r.RenderTemplate(DefaultReleaseTemplate, wr)
+}
+
+func (r Release) RenderTemplate(tmpl *template.Template, wr io.Writer) error {
+	return tmpl.Execute(wr, r)
 }
@@ -16,17 +16,13 @@ limitations under the License.
 
 package domain
 
-import (
-	"fmt"
-)
-
 type Tag struct {
 	Target  *Target
 	Version *Version
 }
 
 func (t Tag) String() string {
-	return fmt.Sprintf("%s@%d.%d.%d", t.Target.Id, t.Version.Year, t.Version.Week, t.Version.Patch)
+	return t.Target.TagName(*t.Version)
 }
 
 func (t Tag) Branch() string {
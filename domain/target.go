@@ -16,7 +16,36 @@ limitations under the License.
 
 package domain
 
+import "gotofu.com/mochi/utils/glob"
+
 type Target struct {
-	Name string
-	Id   string
+	Name       string
+	Id         string
+	Paths      []string
+	Changelog  string
+	Versioning string
+	// TagFormat and BranchFormat name the tags and release branches of the
+	// target, e.g. "{target}/v{version}".
+	TagFormat    string
+	BranchFormat string
+	// VersionFiles are rewritten with the new version on release.
+	VersionFiles []VersionFile
+}
+
+func (t Target) Scheme() VersioningScheme {
+	if scheme, err := GetVersioningScheme(t.Versioning); err == nil {
+		return scheme
+	}
+
+	return VersioningSchemes[DefaultVersioningScheme]
+}
+
+func (t Target) Owns(path string) bool {
+	for _, pattern := range t.Paths {
+		if glob.Match(pattern, path) {
+			return true
+		}
+	}
+
+	return false
 }
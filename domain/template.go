@@ -0,0 +1,51 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateFuncs are the functions available to release note templates.
+var TemplateFuncs = template.FuncMap{
+	"join":      strings.Join,
+	"lower":     strings.ToLower,
+	"upper":     strings.ToUpper,
+	"trimSpace": strings.TrimSpace,
+	"indent": func(spaces int, s string) string {
+		padding := strings.Repeat(" ", spaces)
+		return padding + strings.ReplaceAll(s, "\n", "\n"+padding)
+	},
+	"date": func(layout string, t time.Time) string {
+		return t.Format(layout)
+	},
+	"plural": func(count int, singular string, plural string) string {
+		if count == 1 {
+			return singular
+		}
+		return plural
+	},
+	"link": func(text string, url string) string {
+		if url == "" {
+			return text
+		}
+		return fmt.Sprintf("[%s](%s)", text, url)
+	},
+}
@@ -18,44 +18,78 @@ package domain
 
 import (
 	"fmt"
-	"time"
+	"regexp"
+	"strings"
 )
 
+// Version is a version made of three numbers, whose meaning depends on the
+// versioning scheme of the target, e.g. year, week and patch for ISO week
+// calendar versioning, or major, minor and patch for semantic versioning.
+// Pre-release versions additionally carry a label and a counter, as in
+// 2024.38.0-rc.1.
 type Version struct {
-	Year  int
-	Week  int
-	Patch int
+	Major     int
+	Minor     int
+	Patch     int
+	PreLabel  string
+	PreNumber int
+}
+
+var preLabelRegex = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+
+func ValidatePreLabel(label string) error {
+	if !preLabelRegex.MatchString(label) {
+		return fmt.Errorf("pre-release label %s must only contain letters and digits", label)
+	}
+
+	return nil
 }
 
 func (v Version) String() string {
-	return fmt.Sprintf("%d.%d.%d", v.Year, v.Week, v.Patch)
+	if v.IsPrerelease() {
+		return fmt.Sprintf("%d.%d.%d-%s.%d", v.Major, v.Minor, v.Patch, v.PreLabel, v.PreNumber)
+	}
+
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
 }
 
 func (v Version) Branch(t *Target) string {
-	return fmt.Sprintf("release/%s/%s", t.Id, v.String())
+	return t.BranchName(v)
+}
+
+func (v Version) IsPrerelease() bool {
+	return v.PreLabel != ""
+}
+
+// Final returns the version without its pre-release part.
+func (v Version) Final() *Version {
+	return &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+}
+
+func (v Version) WithPrerelease(label string, number int) *Version {
+	return &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch, PreLabel: label, PreNumber: number}
 }
 
-func (v Version) Validate() error {
-	if v.Year <= 0 || v.Year > time.Now().Year() {
-		return fmt.Errorf("year is not in the expected range")
+// Compare orders versions by their numbers, with pre-releases coming before
+// the final version.
+func (v Version) Compare(other *Version) int {
+	if v.Major != other.Major {
+		return v.Major - other.Major
 	}
-	if v.Week <= 0 || v.Week > 53 {
-		return fmt.Errorf("week is not in the expected range")
+	if v.Minor != other.Minor {
+		return v.Minor - other.Minor
 	}
-	if v.Patch < 0 {
-		return fmt.Errorf("patch is not in the expected range")
+	if v.Patch != other.Patch {
+		return v.Patch - other.Patch
 	}
-	return nil
-}
 
-func (v Version) Bump() *Version {
-	return &Version{
-		Year:  v.Year,
-		Week:  v.Week,
-		Patch: v.Patch + 1,
+	switch {
+	case v.IsPrerelease() && !other.IsPrerelease():
+		return -1
+	case !v.IsPrerelease() && other.IsPrerelease():
+		return 1
+	case v.PreLabel != other.PreLabel:
+		return strings.Compare(v.PreLabel, other.PreLabel)
 	}
-}
-
-func (v Version) IsSameWeek(other *Version) bool {
-	return v.Year == other.Year && v.Week == other.Week
+	return v.PreNumber - other.PreNumber
 }
@@ -0,0 +1,77 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// VersionFile is a project file declaring the version of a target, which is
+// rewritten when the target is released. The first capture group of each
+// pattern is replaced with the new version.
+type VersionFile struct {
+	Path    string
+	Pattern string
+}
+
+var (
+	jsonVersionPattern      = regexp.MustCompile(`"version"\s*:\s*"([^"]*)"`)
+	chartVersionPattern     = regexp.MustCompile(`(?m)^version:[ \t]*["']?([^"'\s#]+)`)
+	chartAppVersionPattern  = regexp.MustCompile(`(?m)^appVersion:[ \t]*["']?([^"'\s#]+)`)
+	pyprojectVersionPattern = regexp.MustCompile(`(?m)^version[ \t]*=[ \t]*["']([^"']*)["']`)
+	goVersionPattern        = regexp.MustCompile(`\bVersion\s*(?:string\s*)?=\s*"([^"]*)"`)
+)
+
+// VersionPattern matches a version declaration. Optional declarations, like
+// the appVersion of a Helm chart, are only replaced when present.
+type VersionPattern struct {
+	Regexp   *regexp.Regexp
+	Optional bool
+}
+
+// Patterns returns the patterns matching the version declarations in the
+// file, either the configured one or the built-in ones for the well-known
+// file types.
+func (f VersionFile) Patterns() ([]VersionPattern, error) {
+	if f.Pattern != "" {
+		pattern, err := regexp.Compile(f.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern for version file %s: %w", f.Path, err)
+		}
+		if pattern.NumSubexp() < 1 {
+			return nil, fmt.Errorf("pattern for version file %s must have a capture group for the version", f.Path)
+		}
+		return []VersionPattern{{Regexp: pattern}}, nil
+	}
+
+	name := path.Base(f.Path)
+	switch {
+	case name == "Chart.yaml":
+		return []VersionPattern{{Regexp: chartVersionPattern}, {Regexp: chartAppVersionPattern, Optional: true}}, nil
+	case name == "pyproject.toml":
+		return []VersionPattern{{Regexp: pyprojectVersionPattern}}, nil
+	case strings.HasSuffix(name, ".json"):
+		return []VersionPattern{{Regexp: jsonVersionPattern}}, nil
+	case strings.HasSuffix(name, ".go"):
+		return []VersionPattern{{Regexp: goVersionPattern}}, nil
+	}
+
+	return nil, fmt.Errorf("unknown type of version file %s, configure a pattern for it", f.Path)
+}
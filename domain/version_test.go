@@ -0,0 +1,139 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import "testing"
+
+func TestVersionCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Version
+		want int
+	}{
+		{
+			name: "equal",
+			a:    Version{Major: 1, Minor: 2, Patch: 3},
+			b:    Version{Major: 1, Minor: 2, Patch: 3},
+			want: 0,
+		},
+		{
+			name: "numbers before pre-release",
+			a:    Version{Major: 1, Minor: 2, Patch: 3, PreLabel: "rc", PreNumber: 1},
+			b:    Version{Major: 1, Minor: 2, Patch: 2},
+			want: 1,
+		},
+		{
+			name: "pre-release before final",
+			a:    Version{Major: 1, Minor: 2, Patch: 3, PreLabel: "rc", PreNumber: 5},
+			b:    Version{Major: 1, Minor: 2, Patch: 3},
+			want: -1,
+		},
+		{
+			name: "pre-release numbers compared numerically",
+			a:    Version{Major: 1, Minor: 2, Patch: 3, PreLabel: "rc", PreNumber: 9},
+			b:    Version{Major: 1, Minor: 2, Patch: 3, PreLabel: "rc", PreNumber: 10},
+			want: -1,
+		},
+		{
+			name: "pre-release labels",
+			a:    Version{Major: 1, Minor: 2, Patch: 3, PreLabel: "beta", PreNumber: 2},
+			b:    Version{Major: 1, Minor: 2, Patch: 3, PreLabel: "rc", PreNumber: 1},
+			want: -1,
+		},
+	}
+
+	sign := func(n int) int {
+		switch {
+		case n < 0:
+			return -1
+		case n > 0:
+			return 1
+		}
+		return 0
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sign(tt.a.Compare(&tt.b)); got != tt.want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := sign(tt.b.Compare(&tt.a)); got != -tt.want {
+				t.Errorf("%s.Compare(%s) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrerelease(t *testing.T) {
+	tests := []struct {
+		name       string
+		versioning string
+		raw        string
+		want       Version
+		wantErr    bool
+	}{
+		{
+			name: "final",
+			raw:  "2024.38.1",
+			want: Version{Major: 2024, Minor: 38, Patch: 1},
+		},
+		{
+			name: "pre-release",
+			raw:  "2024.38.0-rc.10",
+			want: Version{Major: 2024, Minor: 38, Patch: 0, PreLabel: "rc", PreNumber: 10},
+		},
+		{
+			name:       "semver pre-release with a v prefix",
+			versioning: "semver",
+			raw:        "v2.0.0-beta2.1",
+			want:       Version{Major: 2, Minor: 0, Patch: 0, PreLabel: "beta2", PreNumber: 1},
+		},
+		{
+			name:    "missing pre-release number",
+			raw:     "2024.38.0-rc",
+			wantErr: true,
+		},
+		{
+			name:    "zero pre-release number",
+			raw:     "2024.38.0-rc.0",
+			wantErr: true,
+		},
+		{
+			name:    "invalid pre-release label",
+			raw:     "2024.38.0-rc-1.1",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Target{Versioning: tt.versioning}.Scheme().Parse(tt.raw)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Parse(%q) = %s, want an error", tt.raw, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tt.raw, err)
+			}
+			if *got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.raw, *got, tt.want)
+			}
+		})
+	}
+}
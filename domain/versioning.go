@@ -0,0 +1,242 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type BumpLevel int
+
+const (
+	BumpPatch BumpLevel = iota
+	BumpMinor
+	BumpMajor
+)
+
+var bumpLevelNames = []string{"patch", "minor", "major"}
+
+func (b BumpLevel) String() string {
+	return bumpLevelNames[b]
+}
+
+func ParseBumpLevel(name string) (BumpLevel, error) {
+	if i := slices.Index(bumpLevelNames, name); i >= 0 {
+		return BumpLevel(i), nil
+	}
+
+	return BumpPatch, fmt.Errorf("unknown bump level %s, expected one of %s", name, strings.Join(bumpLevelNames, ", "))
+}
+
+// VersioningScheme defines how the versions of a target are parsed,
+// validated, incremented and formatted.
+type VersioningScheme interface {
+	Parse(raw string) (*Version, error)
+	Validate(v Version) error
+	// Next returns the version following the latest one, which is nil for
+	// the first release. The bump level is only used by schemes which are
+	// not based on the release date.
+	Next(latest *Version, now time.Time, bump BumpLevel) *Version
+	// UsesBumpLevel reports whether Next depends on the bump level.
+	UsesBumpLevel() bool
+}
+
+const DefaultVersioningScheme = "calver-week"
+
+var VersioningSchemes = map[string]VersioningScheme{
+	"calver-week":  isoWeekScheme{},
+	"calver-month": monthScheme{},
+	"semver":       semverScheme{},
+}
+
+func GetVersioningScheme(name string) (VersioningScheme, error) {
+	if name == "" {
+		name = DefaultVersioningScheme
+	}
+
+	scheme, ok := VersioningSchemes[name]
+	if !ok {
+		var names []string
+		for n := range VersioningSchemes {
+			names = append(names, n)
+		}
+		slices.Sort(names)
+
+		return nil, fmt.Errorf("unknown versioning scheme %s, expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	return scheme, nil
+}
+
+var versionRegex = regexp.MustCompile(`^(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z]+)\.(\d+))?$`)
+
+func parseNumbers(raw string, format string) (*Version, error) {
+	var (
+		v   Version
+		err error
+	)
+
+	parts := versionRegex.FindStringSubmatch(raw)
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("version is not in the format %s, optionally followed by -label.number", format)
+	}
+
+	if v.Major, err = strconv.Atoi(parts[1]); err != nil {
+		return nil, fmt.Errorf("version %s is not a valid number", parts[1])
+	}
+	if v.Minor, err = strconv.Atoi(parts[2]); err != nil {
+		return nil, fmt.Errorf("version %s is not a valid number", parts[2])
+	}
+	if v.Patch, err = strconv.Atoi(parts[3]); err != nil {
+		return nil, fmt.Errorf("version %s is not a valid number", parts[3])
+	}
+	if parts[4] != "" {
+		v.PreLabel = parts[4]
+		if v.PreNumber, err = strconv.Atoi(parts[5]); err != nil || v.PreNumber <= 0 {
+			return nil, fmt.Errorf("pre-release number %s is not a valid number", parts[5])
+		}
+	}
+
+	return &v, nil
+}
+
+// isoWeekScheme versions releases as year.week.patch, using ISO weeks.
+type isoWeekScheme struct{}
+
+func (s isoWeekScheme) Parse(raw string) (*Version, error) {
+	v, err := parseNumbers(raw, "year.week.patch")
+	if err != nil {
+		return nil, err
+	}
+
+	return v, s.Validate(*v)
+}
+
+func (isoWeekScheme) Validate(v Version) error {
+	if v.Major <= 0 || v.Major > time.Now().Year() {
+		return fmt.Errorf("year is not in the expected range")
+	}
+	if v.Minor <= 0 || v.Minor > 53 {
+		return fmt.Errorf("week is not in the expected range")
+	}
+	if v.Patch < 0 {
+		return fmt.Errorf("patch is not in the expected range")
+	}
+	return nil
+}
+
+func (isoWeekScheme) Next(latest *Version, now time.Time, bump BumpLevel) *Version {
+	year, week := now.ISOWeek()
+	return nextInPeriod(latest, year, week)
+}
+
+func (isoWeekScheme) UsesBumpLevel() bool {
+	return false
+}
+
+// monthScheme versions releases as year.month.patch.
+type monthScheme struct{}
+
+func (s monthScheme) Parse(raw string) (*Version, error) {
+	v, err := parseNumbers(raw, "year.month.patch")
+	if err != nil {
+		return nil, err
+	}
+
+	return v, s.Validate(*v)
+}
+
+func (monthScheme) Validate(v Version) error {
+	if v.Major <= 0 || v.Major > time.Now().Year() {
+		return fmt.Errorf("year is not in the expected range")
+	}
+	if v.Minor <= 0 || v.Minor > 12 {
+		return fmt.Errorf("month is not in the expected range")
+	}
+	if v.Patch < 0 {
+		return fmt.Errorf("patch is not in the expected range")
+	}
+	return nil
+}
+
+func (monthScheme) Next(latest *Version, now time.Time, bump BumpLevel) *Version {
+	return nextInPeriod(latest, now.Year(), int(now.Month()))
+}
+
+func (monthScheme) UsesBumpLevel() bool {
+	return false
+}
+
+// nextInPeriod bumps the patch of the latest version if it was released in
+// the same period, and starts a new period otherwise.
+func nextInPeriod(latest *Version, year int, period int) *Version {
+	if latest != nil && latest.Major == year && latest.Minor == period {
+		return &Version{Major: year, Minor: period, Patch: latest.Patch + 1}
+	}
+
+	return &Version{Major: year, Minor: period, Patch: 0}
+}
+
+// semverScheme versions releases as major.minor.patch, following semantic
+// versioning.
+type semverScheme struct{}
+
+func (s semverScheme) Parse(raw string) (*Version, error) {
+	v, err := parseNumbers(strings.TrimPrefix(raw, "v"), "major.minor.patch")
+	if err != nil {
+		return nil, err
+	}
+
+	return v, s.Validate(*v)
+}
+
+func (semverScheme) Validate(v Version) error {
+	if v.Major < 0 || v.Minor < 0 || v.Patch < 0 {
+		return fmt.Errorf("version numbers must not be negative")
+	}
+	return nil
+}
+
+// Next starts at 0.1.0 when there is no previous release, or 1.0.0 if the
+// first release requires a major bump, e.g. for a breaking change.
+func (semverScheme) Next(latest *Version, now time.Time, bump BumpLevel) *Version {
+	if latest == nil {
+		if bump == BumpMajor {
+			return &Version{Major: 1}
+		}
+		return &Version{Minor: 1}
+	}
+
+	next := *latest
+	switch bump {
+	case BumpMajor:
+		return &Version{Major: next.Major + 1}
+	case BumpMinor:
+		return &Version{Major: next.Major, Minor: next.Minor + 1}
+	default:
+		return &Version{Major: next.Major, Minor: next.Minor, Patch: next.Patch + 1}
+	}
+}
+
+func (semverScheme) UsesBumpLevel() bool {
+	return true
+}
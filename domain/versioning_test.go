@@ -0,0 +1,73 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSemverNext(t *testing.T) {
+	tests := []struct {
+		name   string
+		latest *Version
+		bump   BumpLevel
+		want   Version
+	}{
+		{
+			name: "first release",
+			bump: BumpPatch,
+			want: Version{Minor: 1},
+		},
+		{
+			name: "first release with a minor bump",
+			bump: BumpMinor,
+			want: Version{Minor: 1},
+		},
+		{
+			name: "first release with a major bump",
+			bump: BumpMajor,
+			want: Version{Major: 1},
+		},
+		{
+			name:   "patch",
+			latest: &Version{Major: 1, Minor: 2, Patch: 3},
+			bump:   BumpPatch,
+			want:   Version{Major: 1, Minor: 2, Patch: 4},
+		},
+		{
+			name:   "minor",
+			latest: &Version{Major: 1, Minor: 2, Patch: 3},
+			bump:   BumpMinor,
+			want:   Version{Major: 1, Minor: 3},
+		},
+		{
+			name:   "major",
+			latest: &Version{Major: 1, Minor: 2, Patch: 3},
+			bump:   BumpMajor,
+			want:   Version{Major: 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (semverScheme{}).Next(tt.latest, time.Now(), tt.bump); *got != tt.want {
+				t.Errorf("Next() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
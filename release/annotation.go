@@ -0,0 +1,71 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gotofu.com/mochi/domain"
+)
+
+// AnnotationTrailer is the git trailer holding the JSON representation of a
+// release in the annotation of its tag.
+const AnnotationTrailer = "Mochi-Release"
+
+// ErrNoStoredRelease is returned for tags whose annotation does not hold the
+// release, e.g. those created before releases were stored in tags.
+var ErrNoStoredRelease = fmt.Errorf("tag annotation does not contain a %s trailer", AnnotationTrailer)
+
+// Annotation returns the message of the annotated tag for the release: the
+// rendered release notes, followed by a trailer with their JSON
+// representation, so the release can be recovered from the tag alone.
+func Annotation(release *domain.Release) (string, error) {
+	var notes bytes.Buffer
+	if err := renderMarkdown(release, &notes); err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(NewJSONRelease(release))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s\n\n%s\n\n%s: %s\n", release.Tag.String(), strings.TrimSpace(notes.String()), AnnotationTrailer, data), nil
+}
+
+// ParseAnnotation decodes the release stored in the trailer of a tag
+// annotation. The lines are scanned from the end, as the release notes above
+// the trailer may contain anything.
+func ParseAnnotation(annotation string) (*JSONRelease, error) {
+	var r JSONRelease
+
+	prefix := AnnotationTrailer + ": "
+	lines := strings.Split(annotation, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if data, ok := strings.CutPrefix(strings.TrimSuffix(lines[i], "\r"), prefix); ok {
+			if err := json.Unmarshal([]byte(data), &r); err != nil {
+				return nil, fmt.Errorf("could not decode release from tag annotation: %w", err)
+			}
+			return &r, nil
+		}
+	}
+
+	return nil, ErrNoStoredRelease
+}
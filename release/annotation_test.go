@@ -0,0 +1,113 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/domain"
+)
+
+func TestAnnotationRoundTrip(t *testing.T) {
+	config.Configuration = &config.Config{}
+
+	release := testRelease(
+		&domain.Change{Message: "Fix the login\n\nSessions expired too early.", Issues: []string{"12"}, Authors: []string{"alice"}},
+		&domain.Change{Message: "Add a setting", PR: "34"},
+	)
+	release.Date = time.Date(2024, 9, 18, 10, 0, 0, 0, time.UTC)
+	for _, c := range release.Notes[0].Changes {
+		c.Change.Type = release.Notes[0].Type
+		c.Change.Targets = []*domain.Target{release.Tag.Target}
+	}
+
+	annotation, err := Annotation(release)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseAnnotation(annotation)
+	if err != nil {
+		t.Fatalf("ParseAnnotation() returned error: %v", err)
+	}
+
+	got, _ := json.Marshal(parsed)
+	want, _ := json.Marshal(NewJSONRelease(release))
+	if string(got) != string(want) {
+		t.Errorf("ParseAnnotation() = %s, want %s", got, want)
+	}
+}
+
+func TestParseAnnotationLastTrailer(t *testing.T) {
+	// Custom templates may render a change quoting the trailer unindented.
+	annotation := "api@2024.38.1\n\n- Document the trailer:\n\nMochi-Release: {\"tag\": \"web@2024.1.0\"}\n\nMochi-Release: {\"tag\": \"api@2024.38.1\"}\n"
+
+	r, err := ParseAnnotation(annotation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Tag != "api@2024.38.1" {
+		t.Errorf("ParseAnnotation() tag = %s, want api@2024.38.1", r.Tag)
+	}
+}
+
+func TestParseAnnotationInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		wantErr    error
+	}{
+		{
+			name:       "no annotation",
+			annotation: "",
+			wantErr:    ErrNoStoredRelease,
+		},
+		{
+			name:       "missing trailer",
+			annotation: "api@2024.38.1\n\n## Bug Fixes\n\n- Fix the login\n",
+			wantErr:    ErrNoStoredRelease,
+		},
+		{
+			name:       "trailer without separator",
+			annotation: "api@2024.38.1\n\nMochi-Release:{}\n",
+			wantErr:    ErrNoStoredRelease,
+		},
+		{
+			name:       "malformed trailer",
+			annotation: "api@2024.38.1\n\nMochi-Release: {\"tag\": \n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseAnnotation(tt.annotation)
+			if err == nil {
+				t.Fatalf("ParseAnnotation() = %+v, want an error", r)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseAnnotation() = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && errors.Is(err, ErrNoStoredRelease) {
+				t.Errorf("ParseAnnotation() = %v, want a decoding error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/domain"
+)
+
+// UpdateChangelog inserts the release in the changelog file configured for
+// its target, right after the changelog marker.
+func UpdateChangelog(release *domain.Release) error {
+	path := release.Tag.Target.Changelog
+
+	tmpl, err := ChangelogTemplate(release.Tag.Target)
+	if err != nil {
+		return err
+	}
+
+	var entry bytes.Buffer
+	if err := release.RenderTemplate(tmpl, &entry); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	content := insertChangelogEntry(string(data), strings.TrimSpace(entry.String()), config.Configuration.ChangelogMarker)
+
+	slog.Debug("Updating changelog.", "file", path)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// insertChangelogEntry inserts the entry right after the marker. Without the
+// marker, it is inserted after the first top-level heading, which is usually
+// the title of the changelog, or prepended if there is none.
+func insertChangelogEntry(content string, entry string, marker string) string {
+	if content == "" {
+		if marker != "" {
+			return marker + "\n\n" + entry + "\n"
+		}
+		return entry + "\n"
+	}
+
+	if marker != "" {
+		if end, ok := lineEnd(content, marker); ok {
+			return insertAt(content, end, entry)
+		}
+	}
+
+	for offset := 0; offset < len(content); {
+		line, _, _ := strings.Cut(content[offset:], "\n")
+		if strings.HasPrefix(line, "# ") {
+			return insertAt(content, offset+len(line), entry)
+		}
+		offset += len(line) + 1
+	}
+
+	return entry + "\n\n" + strings.TrimLeft(content, "\n")
+}
+
+// lineEnd returns the end of the first line of the content containing the
+// text.
+func lineEnd(content string, text string) (int, bool) {
+	i := strings.Index(content, text)
+	if i < 0 {
+		return 0, false
+	}
+
+	end := i + len(text)
+	if j := strings.Index(content[end:], "\n"); j >= 0 {
+		return end + j, true
+	}
+
+	return len(content), true
+}
+
+func insertAt(content string, end int, entry string) string {
+	rest := strings.TrimLeft(content[end:], "\n")
+	if rest != "" {
+		rest = "\n" + rest
+	}
+
+	return strings.TrimRight(content[:end], "\n") + "\n\n" + entry + "\n" + rest
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import "testing"
+
+func TestInsertChangelogEntry(t *testing.T) {
+	const (
+		marker = "<!-- mochi -->"
+		entry  = "## 2024.38.1\n\n- Fix"
+	)
+
+	tests := []struct {
+		name    string
+		content string
+		marker  string
+		want    string
+	}{
+		{
+			name:    "empty content",
+			content: "",
+			marker:  marker,
+			want:    marker + "\n\n" + entry + "\n",
+		},
+		{
+			name:    "empty content without marker",
+			content: "",
+			want:    entry + "\n",
+		},
+		{
+			name:    "marker present",
+			content: "# Changelog\n\n" + marker + "\n\n## 2024.38.0\n\n- Feature\n",
+			marker:  marker,
+			want:    "# Changelog\n\n" + marker + "\n\n" + entry + "\n\n## 2024.38.0\n\n- Feature\n",
+		},
+		{
+			name:    "marker at end of file without trailing newline",
+			content: "# Changelog\n\n" + marker,
+			marker:  marker,
+			want:    "# Changelog\n\n" + marker + "\n\n" + entry + "\n",
+		},
+		{
+			name:    "marker missing",
+			content: "# Changelog\n\nAll notable changes are documented here.\n\n## 2024.38.0\n\n- Feature\n",
+			marker:  marker,
+			want:    "# Changelog\n\n" + entry + "\n\nAll notable changes are documented here.\n\n## 2024.38.0\n\n- Feature\n",
+		},
+		{
+			name:    "marker missing without title",
+			content: "## 2024.38.0\n\n- Feature\n",
+			marker:  marker,
+			want:    entry + "\n\n## 2024.38.0\n\n- Feature\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := insertChangelogEntry(tt.content, entry, tt.marker); got != tt.want {
+				t.Errorf("insertChangelogEntry() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
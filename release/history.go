@@ -0,0 +1,163 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/tag"
+	"gotofu.com/mochi/utils/git"
+)
+
+type TaggedRelease struct {
+	Tag    *domain.Tag
+	Date   time.Time
+	Commit string
+}
+
+// List returns the releases of the target, from the oldest to the newest.
+func List(target *domain.Target) ([]*TaggedRelease, error) {
+	refs, err := git.Tags(target.TagPattern(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	releases := []*TaggedRelease{}
+	for _, ref := range refs {
+		t, err := tag.Parse(ref.Name)
+		if err != nil {
+			slog.Debug("Ignoring tag not matching the tag format.", "tag", ref.Name, "error", err)
+			continue
+		}
+		if t.Target.Id != target.Id {
+			continue
+		}
+
+		releases = append(releases, &TaggedRelease{
+			Tag:    t,
+			Date:   ref.Date,
+			Commit: ref.Commit,
+		})
+	}
+
+	slices.SortFunc(releases, func(a, b *TaggedRelease) int {
+		return a.Tag.Version.Compare(b.Tag.Version)
+	})
+
+	return releases, nil
+}
+
+// Show returns a past release, recovered from the annotation of its tag.
+func Show(name string) (*domain.Release, error) {
+	annotation, err := git.TagAnnotation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := ParseAnnotation(annotation)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Release()
+}
+
+// Cumulative merges the releases of the target after the from version, up to
+// and including the to version, into a single release. Either bound may be
+// nil to leave the range open. Changes are grouped by type in the configured
+// order, and duplicated changes are only kept once. Releases whose tag does
+// not hold their notes are skipped and returned separately.
+func Cumulative(target *domain.Target, from *domain.Version, to *domain.Version) (*domain.Release, []*TaggedRelease, error) {
+	var (
+		typeIds []string
+		changes = make(map[string][]*domain.ReleaseChange)
+		types   = make(map[string]*domain.ChangeType)
+		seen    = make(map[string]bool)
+		last    *TaggedRelease
+		skipped []*TaggedRelease
+	)
+
+	releases, err := List(target)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, t := range config.Configuration.Types {
+		typeIds = append(typeIds, t.Id)
+	}
+
+	for _, r := range releases {
+		if from != nil && r.Tag.Version.Compare(from) <= 0 {
+			continue
+		}
+		if to != nil && r.Tag.Version.Compare(to) > 0 {
+			continue
+		}
+
+		last = r
+
+		rel, err := Show(r.Tag.String())
+		if errors.Is(err, ErrNoStoredRelease) {
+			skipped = append(skipped, r)
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+
+		for _, note := range rel.Notes {
+			if !slices.Contains(typeIds, note.Type.Id) {
+				typeIds = append(typeIds, note.Type.Id)
+			}
+			types[note.Type.Id] = note.Type
+
+			for _, c := range note.Changes {
+				key := note.Type.Id + "\x00" + c.Change.Message
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				changes[note.Type.Id] = append(changes[note.Type.Id], c)
+			}
+		}
+	}
+
+	if last == nil {
+		return nil, nil, fmt.Errorf("no releases found for %s in the given range", target.Id)
+	}
+
+	release := domain.Release{
+		Tag:   last.Tag,
+		Date:  last.Date,
+		Notes: []*domain.ReleaseNote{},
+	}
+	for _, id := range typeIds {
+		if len(changes[id]) > 0 {
+			release.Notes = append(release.Notes, &domain.ReleaseNote{
+				Type:    types[id],
+				Changes: changes[id],
+			})
+		}
+	}
+
+	return &release, skipped, nil
+}
@@ -0,0 +1,187 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"time"
+
+	"gotofu.com/mochi/change_type"
+	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/target"
+	"gotofu.com/mochi/version"
+)
+
+// JSONSchemaVersion is bumped on any backwards incompatible change to the
+// JSON representation of a release. Fields may be added without bumping it.
+const JSONSchemaVersion = 1
+
+// JSONRelease is the JSON representation of a release:
+//
+//	{
+//	  "schemaVersion": 1,
+//	  "tag": "api@2024.38.1",
+//	  "target": {"id": "api", "name": "API"},
+//	  "version": "2024.38.1",
+//	  "date": "2024-09-18T10:00:00Z",
+//	  "sections": [
+//	    {
+//	      "type": {"id": "feature", "name": "Feature", "title": "Features"},
+//	      "changes": [
+//	        {
+//	          "message": "Add the thing",
+//	          "summary": "Add the thing",
+//	          "targets": ["api"],
+//	          "issues": ["123"],
+//	          "pr": "456",
+//	          "authors": ["alice"],
+//	          "metadata": {"key": "value"},
+//	          "breaking": true
+//	        }
+//	      ]
+//	    }
+//	  ]
+//	}
+//
+// Sections follow the order of the configured types, and empty optional
+// fields are omitted.
+type JSONRelease struct {
+	SchemaVersion int           `json:"schemaVersion"`
+	Tag           string        `json:"tag"`
+	Target        JSONTarget    `json:"target"`
+	Version       string        `json:"version"`
+	Date          time.Time     `json:"date"`
+	Sections      []JSONSection `json:"sections"`
+}
+
+type JSONTarget struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type JSONType struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+type JSONSection struct {
+	Type    JSONType     `json:"type"`
+	Changes []JSONChange `json:"changes"`
+}
+
+type JSONChange struct {
+	Message  string            `json:"message"`
+	Summary  string            `json:"summary"`
+	Targets  []string          `json:"targets"`
+	Issues   []string          `json:"issues,omitempty"`
+	PR       string            `json:"pr,omitempty"`
+	Authors  []string          `json:"authors,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Breaking bool              `json:"breaking,omitempty"`
+}
+
+func NewJSONRelease(release *domain.Release) JSONRelease {
+	r := JSONRelease{
+		SchemaVersion: JSONSchemaVersion,
+		Tag:           release.Tag.String(),
+		Target:        JSONTarget{Id: release.Tag.Target.Id, Name: release.Tag.Target.Name},
+		Version:       release.Tag.Version.String(),
+		Date:          release.Date.Truncate(time.Second),
+		Sections:      []JSONSection{},
+	}
+
+	for _, note := range release.Notes {
+		section := JSONSection{
+			Type:    JSONType{Id: note.Type.Id, Name: note.Type.Name, Title: note.Type.Title},
+			Changes: []JSONChange{},
+		}
+		for _, c := range note.Changes {
+			section.Changes = append(section.Changes, JSONChange{
+				Message:  c.Change.Message,
+				Summary:  c.Change.Summary(),
+				Targets:  c.Change.TargetIds(),
+				Issues:   c.Change.Issues,
+				PR:       c.Change.PR,
+				Authors:  c.Change.Authors,
+				Metadata: c.Change.Metadata,
+				Breaking: c.Change.Breaking,
+			})
+		}
+		r.Sections = append(r.Sections, section)
+	}
+
+	return r
+}
+
+// Release converts the JSON representation back into a release. Targets and
+// types are resolved from the configuration when possible, so that renamed
+// titles are picked up, and taken from the JSON representation otherwise.
+func (r JSONRelease) Release() (*domain.Release, error) {
+	t := r.Target.target()
+
+	v, err := version.Parse(t, r.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	release := domain.Release{
+		Tag: &domain.Tag{
+			Target:  t,
+			Version: v,
+		},
+		Date:  r.Date,
+		Notes: []*domain.ReleaseNote{},
+	}
+
+	for _, section := range r.Sections {
+		note := domain.ReleaseNote{Type: section.Type.changeType()}
+		for _, c := range section.Changes {
+			change := domain.Change{
+				Type:     note.Type,
+				Message:  c.Message,
+				Issues:   c.Issues,
+				PR:       c.PR,
+				Authors:  c.Authors,
+				Metadata: c.Metadata,
+				Breaking: c.Breaking,
+			}
+			for _, id := range c.Targets {
+				change.Targets = append(change.Targets, JSONTarget{Id: id, Name: id}.target())
+			}
+			note.Changes = append(note.Changes, &domain.ReleaseChange{Change: &change})
+		}
+		release.Notes = append(release.Notes, &note)
+	}
+
+	return &release, nil
+}
+
+func (t JSONTarget) target() *domain.Target {
+	if configured, err := target.Get(t.Id); err == nil {
+		return configured
+	}
+
+	return &domain.Target{Id: t.Id, Name: t.Name}
+}
+
+func (t JSONType) changeType() *domain.ChangeType {
+	if configured, err := change_type.Get(t.Id); err == nil {
+		return configured
+	}
+
+	return &domain.ChangeType{Id: t.Id, Name: t.Name, Title: t.Title}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strings"
+
+	"gotofu.com/mochi/domain"
+)
+
+type Renderer struct {
+	Render func(release *domain.Release, wr io.Writer) error
+	// Structured renderers produce machine-readable output, which must not be
+	// mixed with any other output.
+	Structured bool
+}
+
+const DefaultFormat = "markdown"
+
+var renderers = map[string]Renderer{
+	"markdown": {Render: renderMarkdown},
+	"text":     {Render: renderText},
+	"html":     {Render: renderHTML, Structured: true},
+	"json":     {Render: renderJSON, Structured: true},
+	"slack":    {Render: renderSlack, Structured: true},
+}
+
+func RegisterRenderer(format string, renderer Renderer) {
+	renderers[format] = renderer
+}
+
+func Formats() []string {
+	var formats []string
+	for format := range renderers {
+		formats = append(formats, format)
+	}
+	slices.Sort(formats)
+
+	return formats
+}
+
+func GetRenderer(format string) (Renderer, error) {
+	renderer, ok := renderers[format]
+	if !ok {
+		return Renderer{}, fmt.Errorf("unsupported format %s, expected one of %s", format, strings.Join(Formats(), ", "))
+	}
+
+	return renderer, nil
+}
+
+func Render(release *domain.Release, format string, wr io.Writer) error {
+	renderer, err := GetRenderer(format)
+	if err != nil {
+		return err
+	}
+
+	return renderer.Render(release, wr)
+}
+
+func renderMarkdown(release *domain.Release, wr io.Writer) error {
+	tmpl, err := Template(release.Tag.Target)
+	if err != nil {
+		return err
+	}
+
+	return release.RenderTemplate(tmpl, wr)
+}
+
+func renderText(release *domain.Release, wr io.Writer) error {
+	fmt.Fprintf(wr, "%s %s (%s)\n", release.Tag.Target.Name, release.Tag.Version, release.Date.Format("2006-01-02"))
+	for _, note := range release.Notes {
+		fmt.Fprintf(wr, "\n%s\n", note.Type.Title)
+		for _, c := range note.Changes {
+			message := strings.ReplaceAll(c.Change.Message, "\n", "\n    ")
+			if references := c.Change.References(); len(references) > 0 {
+				message = fmt.Sprintf("%s (%s)", message, strings.Join(references, ", "))
+			}
+			fmt.Fprintf(wr, "  - %s\n", message)
+		}
+	}
+
+	return nil
+}
+
+var htmlTemplate = template.Must(template.New("release").Funcs(template.FuncMap{
+	"message": htmlMessage,
+}).Parse(`
+{{- range .Notes -}}
+<h2>{{ .Type.Title }}</h2>
+<ul>
+{{- range .Changes }}
+  <li>{{ message .Change }}</li>
+{{- end }}
+</ul>
+{{ end -}}
+`))
+
+// htmlMessage renders the message of a change, keeping its paragraphs and line
+// breaks. The references are appended to the last paragraph.
+func htmlMessage(c *domain.Change) template.HTML {
+	var paragraphs []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(c.Message, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.Trim(paragraph, "\n")
+		if paragraph == "" {
+			continue
+		}
+		paragraphs = append(paragraphs, paragraph)
+	}
+	if references := c.References(); len(references) > 0 {
+		if len(paragraphs) == 0 {
+			paragraphs = append(paragraphs, "")
+		}
+		last := len(paragraphs) - 1
+		paragraphs[last] = strings.TrimLeft(fmt.Sprintf("%s (%s)", paragraphs[last], strings.Join(references, ", ")), " ")
+	}
+
+	for i, paragraph := range paragraphs {
+		paragraphs[i] = strings.ReplaceAll(template.HTMLEscapeString(paragraph), "\n", "<br>\n")
+	}
+	if len(paragraphs) == 1 {
+		return template.HTML(paragraphs[0])
+	}
+
+	return template.HTML("<p>" + strings.Join(paragraphs, "</p><p>") + "</p>")
+}
+
+func renderHTML(release *domain.Release, wr io.Writer) error {
+	return htmlTemplate.Execute(wr, release)
+}
+
+func renderJSON(release *domain.Release, wr io.Writer) error {
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(NewJSONRelease(release))
+}
+
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// slackSectionLimit is the maximum length of the text of a Slack section.
+const slackSectionLimit = 3000
+
+// splitSlackSection joins the lines into as few section texts as possible
+// within the Slack limit, truncating the lines which exceed it on their own.
+func splitSlackSection(lines []string) []string {
+	var (
+		sections []string
+		current  []rune
+	)
+	for _, line := range lines {
+		runes := []rune(line)
+		if len(runes) > slackSectionLimit {
+			runes = append(runes[:slackSectionLimit-1], '…')
+		}
+
+		if len(current) > 0 && len(current)+1+len(runes) > slackSectionLimit {
+			sections = append(sections, string(current))
+			current = nil
+		}
+		if len(current) > 0 {
+			current = append(current, '\n')
+		}
+		current = append(current, runes...)
+	}
+	if len(current) > 0 {
+		sections = append(sections, string(current))
+	}
+
+	return sections
+}
+
+// renderSlack renders a message payload for Slack incoming webhooks, using
+// the Block Kit format.
+func renderSlack(release *domain.Release, wr io.Writer) error {
+	type text struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	}
+	type block struct {
+		Type string `json:"type"`
+		Text *text  `json:"text,omitempty"`
+	}
+
+	title := fmt.Sprintf("%s %s", release.Tag.Target.Name, release.Tag.Version)
+	blocks := []block{{Type: "header", Text: &text{Type: "plain_text", Text: title}}}
+
+	for _, note := range release.Notes {
+		var lines []string
+		lines = append(lines, fmt.Sprintf("*%s*", slackEscaper.Replace(note.Type.Title)))
+		for _, c := range note.Changes {
+			line := slackEscaper.Replace(c.Change.Message)
+			if references := c.Change.References(); len(references) > 0 {
+				line = fmt.Sprintf("%s (%s)", line, slackEscaper.Replace(strings.Join(references, ", ")))
+			}
+			lines = append(lines, "• "+strings.ReplaceAll(line, "\n", "\n    "))
+		}
+
+		for _, section := range splitSlackSection(lines) {
+			blocks = append(blocks, block{Type: "section", Text: &text{Type: "mrkdwn", Text: section}})
+		}
+	}
+
+	encoder := json.NewEncoder(wr)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(struct {
+		Text   string  `json:"text"`
+		Blocks []block `json:"blocks"`
+	}{
+		Text:   title,
+		Blocks: blocks,
+	})
+}
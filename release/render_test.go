@@ -0,0 +1,158 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"gotofu.com/mochi/domain"
+)
+
+func testRelease(changes ...*domain.Change) *domain.Release {
+	note := &domain.ReleaseNote{Type: &domain.ChangeType{Id: "fix", Title: "Bug Fixes"}}
+	for _, c := range changes {
+		note.Changes = append(note.Changes, &domain.ReleaseChange{Change: c})
+	}
+
+	return &domain.Release{
+		Tag: &domain.Tag{
+			Target:  &domain.Target{Id: "api", Name: "API"},
+			Version: &domain.Version{Major: 2024, Minor: 38, Patch: 1},
+		},
+		Notes: []*domain.ReleaseNote{note},
+	}
+}
+
+func TestRenderHTML(t *testing.T) {
+	tests := []struct {
+		name   string
+		change *domain.Change
+		want   string
+	}{
+		{
+			name:   "single line",
+			change: &domain.Change{Message: "Fix <script> handling", PR: "12"},
+			want:   "<li>Fix &lt;script&gt; handling (#12)</li>",
+		},
+		{
+			name:   "line breaks",
+			change: &domain.Change{Message: "Fix the login\non Safari"},
+			want:   "<li>Fix the login<br>\non Safari</li>",
+		},
+		{
+			name:   "paragraphs",
+			change: &domain.Change{Message: "Fix the login\n\nSessions expired too early.", Authors: []string{"alice"}},
+			want:   "<li><p>Fix the login</p><p>Sessions expired too early. (@alice)</p></li>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := renderHTML(testRelease(tt.change), &buf); err != nil {
+				t.Fatal(err)
+			}
+
+			want := "<h2>Bug Fixes</h2>\n<ul>\n  " + tt.want + "\n</ul>\n"
+			if got := buf.String(); got != want {
+				t.Errorf("renderHTML() = %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+type slackPayload struct {
+	Text   string `json:"text"`
+	Blocks []struct {
+		Type string `json:"type"`
+		Text struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"text"`
+	} `json:"blocks"`
+}
+
+func renderSlackPayload(t *testing.T, release *domain.Release) slackPayload {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := renderSlack(release, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var payload slackPayload
+	if err := json.Unmarshal(buf.Bytes(), &payload); err != nil {
+		t.Fatal(err)
+	}
+
+	return payload
+}
+
+func TestRenderSlack(t *testing.T) {
+	payload := renderSlackPayload(t, testRelease(
+		&domain.Change{Message: "Fix <b> & co", Issues: []string{"3"}},
+		&domain.Change{Message: "Fix the login\non Safari"},
+	))
+
+	if payload.Text != "API 2024.38.1" {
+		t.Errorf("text = %q, want %q", payload.Text, "API 2024.38.1")
+	}
+	if len(payload.Blocks) != 2 {
+		t.Fatalf("got %d blocks, want 2", len(payload.Blocks))
+	}
+	if header := payload.Blocks[0]; header.Type != "header" || header.Text.Text != "API 2024.38.1" {
+		t.Errorf("header = %+v", header)
+	}
+
+	want := "*Bug Fixes*\n• Fix &lt;b&gt; &amp; co (#3)\n• Fix the login\n    on Safari"
+	if section := payload.Blocks[1]; section.Type != "section" || section.Text.Type != "mrkdwn" || section.Text.Text != want {
+		t.Errorf("section = %+v, want text %q", section, want)
+	}
+}
+
+func TestRenderSlackSplitsLongSections(t *testing.T) {
+	var changes []*domain.Change
+	for i := 0; i < 30; i++ {
+		changes = append(changes, &domain.Change{Message: strings.Repeat("a", 200)})
+	}
+	changes = append(changes, &domain.Change{Message: strings.Repeat("é", 4000)})
+
+	payload := renderSlackPayload(t, testRelease(changes...))
+
+	var lines []string
+	for _, b := range payload.Blocks[1:] {
+		if n := utf8.RuneCountInString(b.Text.Text); n > slackSectionLimit {
+			t.Errorf("section has %d characters, want at most %d", n, slackSectionLimit)
+		}
+		lines = append(lines, strings.Split(b.Text.Text, "\n")...)
+	}
+
+	// The header, three sections of changes and the truncated change.
+	if len(payload.Blocks) != 5 {
+		t.Errorf("got %d blocks, want 5", len(payload.Blocks))
+	}
+	if len(lines) != len(changes)+1 {
+		t.Fatalf("got %d lines, want %d", len(lines), len(changes)+1)
+	}
+	if last := lines[len(lines)-1]; !strings.HasSuffix(last, "…") || utf8.RuneCountInString(last) != slackSectionLimit {
+		t.Errorf("long line was not truncated to the limit: %d characters", utf8.RuneCountInString(last))
+	}
+}
@@ -20,7 +20,7 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"gotofu.com/mochi/change"
 	"gotofu.com/mochi/config"
@@ -28,34 +28,20 @@ import (
 	"gotofu.com/mochi/utils/git"
 )
 
-func Get(target *domain.Target) []*domain.ReleaseNote {
-	releaseNotes := []*domain.ReleaseNote{}
-	releaseNotesByType := make(map[string][]*domain.ReleaseChange)
-
-	files, _ := filepath.Glob(fmt.Sprintf(".mochi/*-%s-*.md", target.Id))
-	slog.Debug("Release notes files found.", "files", files)
-
-	for _, file := range files {
-		data, err := os.ReadFile(file)
-		if err != nil {
-			slog.Warn("Error reading change file.", "file", file, "error", err)
-			continue
-		}
+func Get(target *domain.Target) ([]*domain.ReleaseNote, error) {
+	index, err := change.LoadIndex()
+	if err != nil {
+		return nil, err
+	}
 
-		change, err := change.Parse(string(data))
-		if err != nil {
-			slog.Warn("Error parsing change file.", "file", file, "error", err)
-			continue
-		}
+	return GetFromIndex(index, target), nil
+}
 
-		releaseNotesByType[change.Type.Id] = append(releaseNotesByType[change.Type.Id], &domain.ReleaseChange{
-			Change: change,
-			File:   file,
-		})
-	}
+func GetFromIndex(index *change.Index, target *domain.Target) []*domain.ReleaseNote {
+	releaseNotes := []*domain.ReleaseNote{}
 
 	for _, t := range config.Configuration.Types {
-		releaseNotesForType := releaseNotesByType[t.Id]
+		releaseNotesForType := index.Get(target.Id, t.Id)
 		slog.Debug("Release notes found for type.", "type", t.Id, "count", len(releaseNotesForType))
 		if len(releaseNotesForType) > 0 {
 			releaseNotes = append(releaseNotes, &domain.ReleaseNote{
@@ -68,50 +54,248 @@ func Get(target *domain.Target) []*domain.ReleaseNote {
 	return releaseNotes
 }
 
-func Commit(release *domain.Release, rebase bool) error {
+type CommitOptions struct {
+	// Rebase rebases the base branch on the release branch instead of merging
+	// the release branch into it.
+	Rebase bool
+	// SkipMerge leaves the base branch untouched, e.g. for hotfixes whose
+	// fixes are already on it.
+	SkipMerge bool
+	// Push pushes the base branch and the tags to the remote.
+	Push bool
+	// Hotfix is set for releases branched from an older release than the
+	// latest one.
+	Hotfix bool
+	// Diverged is set when the release branch does not contain the base
+	// branch.
+	Diverged bool
+}
+
+func (o CommitOptions) Validate() error {
+	if o.Rebase && o.SkipMerge {
+		return fmt.Errorf("--rebase and --no-merge cannot be used together")
+	}
+
+	// A hotfix branch starts from an older release, so rebasing the base
+	// branch on it would rewrite the releases made since.
+	if o.Rebase && o.Hotfix {
+		return fmt.Errorf("a hotfix cannot be finished with --rebase; merge it or pass --no-merge")
+	}
+
+	// Rebasing the base branch on a branch which does not contain it would
+	// drop the commits made on the base branch since.
+	if o.Rebase && o.Diverged {
+		return fmt.Errorf("the release branch does not contain the base branch and cannot be rebased on; merge it instead")
+	}
+
+	return nil
+}
+
+func Commit(release *domain.Release, options CommitOptions) error {
+	return commit([]*domain.Release{release}, release.Tag.Branch(), options, nil)
+}
+
+// CommitTrain finishes the releases of a train with a single commit on the
+// train branch, which also removes the plan of the train, and tags each of
+// them. Nothing is tagged if any of the releases cannot be committed.
+func CommitTrain(releases []*domain.Release, branch string, options CommitOptions) error {
+	return commit(releases, branch, options, removeTrain)
+}
+
+// commit runs cleanUp, if any, after updating the files for the releases so
+// that its changes are part of the release commit.
+func commit(releases []*domain.Release, branch string, options CommitOptions, cleanUp func() error) error {
 	if err := git.EnsureClean(); err != nil {
 		return err
 	}
 
-	for _, note := range release.Notes {
-		for _, change := range note.Changes {
-			slog.Debug("Cleaning up release note file.", "file", change.File)
+	if err := options.Validate(); err != nil {
+		return err
+	}
 
-			if err := os.Remove(change.File); err != nil {
-				return err
-			}
+	var (
+		names        []string
+		annotations  []string
+		versionFiles []versionFileUpdate
+		prerelease   bool
+	)
+	for _, release := range releases {
+		if git.RevisionExists(fmt.Sprintf("refs/tags/%s", release.Tag.String())) {
+			return fmt.Errorf("tag %s already exists", release.Tag.String())
+		}
 
-			if err := git.Add(change.File); err != nil {
+		annotation, err := Annotation(release)
+		if err != nil {
+			return err
+		}
+
+		if options.Push {
+			remoteTags, err := git.RemoteTags(config.Configuration.Remote, release.Tag.String())
+			if err != nil {
 				return err
 			}
+			if len(remoteTags) > 0 {
+				return fmt.Errorf("tag %s already exists on remote %s", release.Tag.String(), config.Configuration.Remote)
+			}
 		}
+
+		updates, err := planVersionFiles(release)
+		if err != nil {
+			return err
+		}
+
+		names = append(names, release.Tag.String())
+		annotations = append(annotations, annotation)
+		versionFiles = append(versionFiles, updates...)
+		prerelease = prerelease || release.Tag.Version.IsPrerelease()
 	}
 
-	if err := git.Commit(fmt.Sprintf("chore: release %s", release.Tag.String())); err != nil {
+	if err := writeVersionFiles(versionFiles); err != nil {
 		return err
 	}
 
-	if err := git.Tag(release.Tag.String()); err != nil {
+	for _, release := range releases {
+		if err := prepare(release); err != nil {
+			return err
+		}
+	}
+
+	if cleanUp != nil {
+		if err := cleanUp(); err != nil {
+			return err
+		}
+	}
+
+	if err := git.Commit(fmt.Sprintf("chore: release %s", strings.Join(names, ", ")), prerelease); err != nil {
 		return err
 	}
 
+	for i, name := range names {
+		if err := git.Tag(name, annotations[i]); err != nil {
+			return err
+		}
+	}
+
 	if err := git.Checkout(config.Configuration.BaseBranch, ""); err != nil {
 		return err
 	}
 
-	if rebase {
-		if err := git.Rebase(release.Tag.Branch()); err != nil {
+	if options.SkipMerge {
+		slog.Debug("Skipping the merge of the release branch.", "branch", branch)
+	} else if options.Rebase {
+		if err := git.Rebase(branch); err != nil {
 			return err
 		}
 	} else {
-		if err := git.Merge(release.Tag.Branch()); err != nil {
-			return err
+		if err := git.Merge(branch); err != nil {
+			return fmt.Errorf("tagged %s, but %w; resolve the conflicts, commit the merge and delete branch %s", strings.Join(names, ", "), err, branch)
 		}
 	}
 
-	if err := git.DeleteBranch(release.Tag.Branch()); err != nil {
+	if err := git.DeleteBranch(branch); err != nil {
 		return err
 	}
 
+	if options.Push {
+		var refs []string
+		if !options.SkipMerge {
+			refs = append(refs, fmt.Sprintf("refs/heads/%s", config.Configuration.BaseBranch))
+		}
+		for _, name := range names {
+			refs = append(refs, fmt.Sprintf("refs/tags/%s", name))
+		}
+
+		slog.Debug("Pushing the release.", "remote", config.Configuration.Remote, "refs", refs)
+
+		if err := git.Push(config.Configuration.Remote, refs); err != nil {
+			return fmt.Errorf("tagged %s, but %w; push them with 'git push --atomic %s %s'", strings.Join(names, ", "), err, config.Configuration.Remote, strings.Join(refs, " "))
+		}
+	}
+
+	return nil
+}
+
+// prepare updates the files of the repository for the release, to be included
+// in the release commit.
+func prepare(release *domain.Release) error {
+	// Pre-releases keep the release note files, so that they are included in
+	// the next pre-release and the final release.
+	prerelease := release.Tag.Version.IsPrerelease()
+
+	if !prerelease {
+		if err := cleanUpChanges(release); err != nil {
+			return err
+		}
+	}
+
+	if changelog := release.Tag.Target.Changelog; changelog != "" && !prerelease {
+		if err := UpdateChangelog(release); err != nil {
+			return err
+		}
+
+		if err := git.Add(changelog); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InferBumpLevel returns the highest bump level required by the changes, along
+// with the changes requiring it.
+func InferBumpLevel(notes []*domain.ReleaseNote) (domain.BumpLevel, []*domain.ReleaseChange) {
+	var (
+		level   = domain.BumpPatch
+		drivers []*domain.ReleaseChange
+	)
+
+	for _, note := range notes {
+		for _, c := range note.Changes {
+			switch changeLevel := c.Change.BumpLevel(); {
+			case changeLevel > level:
+				level = changeLevel
+				drivers = []*domain.ReleaseChange{c}
+			case changeLevel == level:
+				drivers = append(drivers, c)
+			}
+		}
+	}
+
+	return level, drivers
+}
+
+// cleanUpChanges removes the release note files of the release, or only the
+// released target from those declaring several targets. The files are read
+// again, as they may have been rewritten for another release of a train, and
+// the changes of the release are left untouched.
+func cleanUpChanges(release *domain.Release) error {
+	for _, note := range release.Notes {
+		for _, releaseChange := range note.Changes {
+			current, err := change.Read(releaseChange.File)
+			if err != nil {
+				return err
+			}
+
+			current.RemoveTarget(release.Tag.Target.Id)
+			if len(current.Targets) > 0 {
+				slog.Debug("Removing released target from release note file.", "file", releaseChange.File, "target", release.Tag.Target.Id)
+
+				if err := change.Write(current, releaseChange.File); err != nil {
+					return err
+				}
+			} else {
+				slog.Debug("Cleaning up release note file.", "file", releaseChange.File)
+
+				if err := os.Remove(releaseChange.File); err != nil {
+					return err
+				}
+			}
+
+			if err := git.Add(releaseChange.File); err != nil {
+				return err
+			}
+		}
+	}
+
 	return nil
 }
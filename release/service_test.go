@@ -0,0 +1,143 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"gotofu.com/mochi/domain"
+)
+
+func TestCommitOptionsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options CommitOptions
+		wantErr string
+	}{
+		{
+			name:    "merge",
+			options: CommitOptions{},
+		},
+		{
+			name:    "rebase",
+			options: CommitOptions{Rebase: true},
+		},
+		{
+			name:    "merge of a diverged branch",
+			options: CommitOptions{Diverged: true},
+		},
+		{
+			name:    "hotfix without merge",
+			options: CommitOptions{Hotfix: true, Diverged: true, SkipMerge: true},
+		},
+		{
+			name:    "rebase without merge",
+			options: CommitOptions{Rebase: true, SkipMerge: true},
+			wantErr: "--rebase and --no-merge cannot be used together",
+		},
+		{
+			name:    "rebase of a hotfix",
+			options: CommitOptions{Rebase: true, Hotfix: true, Diverged: true},
+			wantErr: "a hotfix cannot be finished with --rebase",
+		},
+		{
+			name:    "rebase of a diverged branch",
+			options: CommitOptions{Rebase: true, Diverged: true},
+			wantErr: "does not contain the base branch",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want no error", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInferBumpLevel(t *testing.T) {
+	var (
+		feature = &domain.ChangeType{Id: "feature", Bump: "minor"}
+		bugfix  = &domain.ChangeType{Id: "bugfix", Bump: "patch"}
+		doc     = &domain.ChangeType{Id: "doc"}
+	)
+	change := func(file string, changeType *domain.ChangeType, breaking bool) *domain.ReleaseChange {
+		return &domain.ReleaseChange{File: file, Change: &domain.Change{Type: changeType, Breaking: breaking}}
+	}
+
+	tests := []struct {
+		name        string
+		changes     []*domain.ReleaseChange
+		wantLevel   domain.BumpLevel
+		wantDrivers []string
+	}{
+		{
+			name:        "patch by default",
+			changes:     []*domain.ReleaseChange{change("a.md", doc, false)},
+			wantLevel:   domain.BumpPatch,
+			wantDrivers: []string{"a.md"},
+		},
+		{
+			name:        "highest level wins",
+			changes:     []*domain.ReleaseChange{change("a.md", bugfix, false), change("b.md", feature, false), change("c.md", doc, false)},
+			wantLevel:   domain.BumpMinor,
+			wantDrivers: []string{"b.md"},
+		},
+		{
+			name:        "breaking change",
+			changes:     []*domain.ReleaseChange{change("a.md", feature, false), change("b.md", bugfix, true)},
+			wantLevel:   domain.BumpMajor,
+			wantDrivers: []string{"b.md"},
+		},
+		{
+			name:        "drivers at equal level",
+			changes:     []*domain.ReleaseChange{change("a.md", feature, false), change("b.md", bugfix, false), change("c.md", feature, false)},
+			wantLevel:   domain.BumpMinor,
+			wantDrivers: []string{"a.md", "c.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Each change gets its own note, as the notes are grouped by type.
+			var notes []*domain.ReleaseNote
+			for _, c := range tt.changes {
+				notes = append(notes, &domain.ReleaseNote{Type: c.Change.Type, Changes: []*domain.ReleaseChange{c}})
+			}
+
+			level, drivers := InferBumpLevel(notes)
+
+			var files []string
+			for _, d := range drivers {
+				files = append(files, d.File)
+			}
+			if level != tt.wantLevel || !slices.Equal(files, tt.wantDrivers) {
+				t.Errorf("InferBumpLevel() = %s, %v, want %s, %v", level, files, tt.wantLevel, tt.wantDrivers)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gotofu.com/mochi/change"
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/domain"
+)
+
+var TemplateDirectory = filepath.Join(change.Directory, "templates")
+
+const templateExtension = ".tmpl"
+
+func templateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"issueUrl": func(id string) string {
+			return expandLink(config.Configuration.Links.Issue, id)
+		},
+		"prUrl": func(id string) string {
+			return expandLink(config.Configuration.Links.PullRequest, id)
+		},
+	}
+}
+
+func expandLink(link string, id string) string {
+	if link == "" || id == "" {
+		return ""
+	}
+
+	return strings.ReplaceAll(link, "{id}", strings.TrimPrefix(id, "#"))
+}
+
+// Template returns the release note template for the target, looking first
+// for a template named after the target in the template directory, then for
+// a default template, and falling back to the built-in template.
+func Template(target *domain.Target) (*template.Template, error) {
+	return loadTemplate(target, "", domain.DefaultReleaseTemplate)
+}
+
+// ChangelogTemplate returns the template used for changelog entries, which is
+// looked up like Template but with a ".changelog" suffix, e.g.
+// "api.changelog.tmpl".
+func ChangelogTemplate(target *domain.Target) (*template.Template, error) {
+	return loadTemplate(target, ".changelog", domain.DefaultChangelogTemplate)
+}
+
+func loadTemplate(target *domain.Target, suffix string, fallback *template.Template) (*template.Template, error) {
+	for _, name := range []string{target.Id, "default"} {
+		file := filepath.Join(TemplateDirectory, name+suffix+templateExtension)
+
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read template %s: %w", file, err)
+		}
+
+		slog.Debug("Using release note template.", "file", file)
+
+		tmpl, err := template.New(filepath.Base(file)).Funcs(domain.TemplateFuncs).Funcs(templateFuncs()).Parse(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("could not parse template %s: %w", file, err)
+		}
+
+		return tmpl, nil
+	}
+
+	return fallback, nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gotofu.com/mochi/change"
+	"gotofu.com/mochi/config"
+	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/target"
+	"gotofu.com/mochi/utils/git"
+	"gotofu.com/mochi/version"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TrainFile holds the plan of a release train. It is committed on the train
+// branch, so the train can be finished from any clone, and removed by the
+// release commit.
+var TrainFile = filepath.Join(change.Directory, "train.yaml")
+
+type Train struct {
+	Name     string
+	Releases []TrainRelease
+}
+
+type TrainRelease struct {
+	Target  string
+	Version string
+}
+
+// NextTrainName returns the name of a new release train started on the given
+// date, numbering the trains started on the same day.
+func NextTrainName(date time.Time) string {
+	name := date.Format("2006-01-02")
+	for i := 2; trainBranchExists(domain.TrainBranchName(config.Configuration.TrainBranchFormat, name)); i++ {
+		name = fmt.Sprintf("%s.%d", date.Format("2006-01-02"), i)
+	}
+
+	return name
+}
+
+func trainBranchExists(branch string) bool {
+	return git.RevisionExists(fmt.Sprintf("refs/heads/%s", branch)) ||
+		git.RevisionExists(fmt.Sprintf("refs/remotes/%s/%s", config.Configuration.Remote, branch))
+}
+
+// StartTrain creates the branch of the train from the current branch, and
+// commits the plan of the train on it.
+func StartTrain(name string, tags []*domain.Tag) (string, error) {
+	branch := domain.TrainBranchName(config.Configuration.TrainBranchFormat, name)
+
+	train := Train{Name: name}
+	for _, t := range tags {
+		train.Releases = append(train.Releases, TrainRelease{
+			Target:  t.Target.Id,
+			Version: t.Version.String(),
+		})
+	}
+
+	data, err := yaml.Marshal(train)
+	if err != nil {
+		return "", err
+	}
+
+	if err := git.Checkout(branch, ""); err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(TrainFile, data, 0o644); err != nil {
+		return "", err
+	}
+
+	if err := git.Add(TrainFile); err != nil {
+		return "", err
+	}
+
+	if err := git.Commit(fmt.Sprintf("chore: start release train %s", name), false); err != nil {
+		return "", err
+	}
+
+	return branch, nil
+}
+
+// ErrNoTrain is returned when no release train is in progress on the current
+// branch.
+var ErrNoTrain = errors.New("no release train in progress on the current branch")
+
+// LoadTrain returns the tags planned by the release train in progress.
+func LoadTrain() ([]*domain.Tag, error) {
+	data, err := os.ReadFile(TrainFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNoTrain
+	} else if err != nil {
+		return nil, fmt.Errorf("could not read release train: %w", err)
+	}
+
+	var train Train
+	if err := yaml.Unmarshal(data, &train); err != nil {
+		return nil, fmt.Errorf("could not decode release train %s: %w", TrainFile, err)
+	}
+
+	var tags []*domain.Tag
+	for _, r := range train.Releases {
+		t, err := target.Get(r.Target)
+		if err != nil {
+			return nil, err
+		}
+
+		v, err := version.Parse(t, r.Version)
+		if err != nil {
+			return nil, err
+		}
+
+		tags = append(tags, &domain.Tag{Target: t, Version: v})
+	}
+
+	return tags, nil
+}
+
+func removeTrain() error {
+	if err := os.Remove(TrainFile); err != nil {
+		return err
+	}
+
+	return git.Add(TrainFile)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+
+	"gotofu.com/mochi/domain"
+	"gotofu.com/mochi/utils/git"
+)
+
+type versionFileUpdate struct {
+	path string
+	data []byte
+	mode fs.FileMode
+}
+
+// planVersionFiles returns the updated content of the version files
+// configured for the target of the release, without writing them, so that a
+// missing version declaration is reported before any file is changed.
+func planVersionFiles(release *domain.Release) ([]versionFileUpdate, error) {
+	var updates []versionFileUpdate
+	for _, f := range release.Tag.Target.VersionFiles {
+		info, err := os.Stat(f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read version file %s: %w", f.Path, err)
+		}
+
+		data, err := os.ReadFile(f.Path)
+		if err != nil {
+			return nil, fmt.Errorf("could not read version file %s: %w", f.Path, err)
+		}
+
+		if data, err = updateVersionFile(f, data, release.Tag.Version.String()); err != nil {
+			return nil, err
+		}
+
+		updates = append(updates, versionFileUpdate{path: f.Path, data: data, mode: info.Mode().Perm()})
+	}
+
+	return updates, nil
+}
+
+// writeVersionFiles writes the updated version files and stages them.
+func writeVersionFiles(updates []versionFileUpdate) error {
+	for _, u := range updates {
+		slog.Debug("Updating version file.", "file", u.path)
+
+		if err := os.WriteFile(u.path, u.data, u.mode); err != nil {
+			return err
+		}
+
+		if err := git.Add(u.path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func updateVersionFile(f domain.VersionFile, data []byte, version string) ([]byte, error) {
+	patterns, err := f.Patterns()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pattern := range patterns {
+		// Only the first declaration is replaced, e.g. the version of a
+		// package.json rather than the ones of its dependencies.
+		match := pattern.Regexp.FindSubmatchIndex(data)
+		if match == nil {
+			if pattern.Optional {
+				continue
+			}
+			return nil, fmt.Errorf("no version found in %s matching %s", f.Path, pattern.Regexp)
+		}
+
+		start, end := match[2], match[3]
+		data = append(data[:start:start], append([]byte(version), data[end:]...)...)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package release
+
+import (
+	"testing"
+
+	"gotofu.com/mochi/domain"
+)
+
+func TestUpdateVersionFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    domain.VersionFile
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "package.json only replaces its own version",
+			file:    domain.VersionFile{Path: "web/package.json"},
+			content: "{\n  \"name\": \"web\",\n  \"version\": \"1.0.0\",\n  \"dependencies\": {\"a\": {\"version\": \"2.0.0\"}}\n}\n",
+			want:    "{\n  \"name\": \"web\",\n  \"version\": \"2024.38.1\",\n  \"dependencies\": {\"a\": {\"version\": \"2.0.0\"}}\n}\n",
+		},
+		{
+			name:    "pyproject.toml",
+			file:    domain.VersionFile{Path: "pyproject.toml"},
+			content: "[project]\nname = \"api\"\nversion = \"0.1.0\"\n",
+			want:    "[project]\nname = \"api\"\nversion = \"2024.38.1\"\n",
+		},
+		{
+			name:    "Chart.yaml with appVersion",
+			file:    domain.VersionFile{Path: "charts/api/Chart.yaml"},
+			content: "apiVersion: v2\nversion: 0.1.0\nappVersion: \"0.1.0\"\n",
+			want:    "apiVersion: v2\nversion: 2024.38.1\nappVersion: \"2024.38.1\"\n",
+		},
+		{
+			name:    "Chart.yaml without appVersion",
+			file:    domain.VersionFile{Path: "charts/api/Chart.yaml"},
+			content: "apiVersion: v2\nname: api\nversion: 0.1.0\n",
+			want:    "apiVersion: v2\nname: api\nversion: 2024.38.1\n",
+		},
+		{
+			name:    "Go constant",
+			file:    domain.VersionFile{Path: "cmd/version.go"},
+			content: "package cmd\n\nconst Version = \"dev\"\n",
+			want:    "package cmd\n\nconst Version = \"2024.38.1\"\n",
+		},
+		{
+			name:    "custom pattern",
+			file:    domain.VersionFile{Path: "VERSION", Pattern: `v=(\S+)`},
+			content: "v=old\n",
+			want:    "v=2024.38.1\n",
+		},
+		{
+			name:    "no version",
+			file:    domain.VersionFile{Path: "web/package.json"},
+			content: "{\n  \"name\": \"web\"\n}\n",
+			wantErr: true,
+		},
+		{
+			name:    "Chart.yaml without version",
+			file:    domain.VersionFile{Path: "Chart.yaml"},
+			content: "apiVersion: v2\nappVersion: \"0.1.0\"\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := updateVersionFile(tt.file, []byte(tt.content), "2024.38.1")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("updateVersionFile() = %q, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("updateVersionFile() returned an error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("updateVersionFile() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
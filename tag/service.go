@@ -17,31 +17,52 @@ limitations under the License.
 package tag
 
 import (
+	"errors"
 	"fmt"
-	"strings"
 
 	"gotofu.com/mochi/domain"
 	"gotofu.com/mochi/target"
 	"gotofu.com/mochi/version"
 )
 
+// ParseFromBranch parses the tag of the release prepared on a release branch,
+// following the branch format of its target.
 func ParseFromBranch(branch string) (*domain.Tag, error) {
-	var (
-		t   domain.Tag
-		err error
-	)
-
-	parts := strings.Split(branch, "/")
-	if len(parts) != 3 {
-		return nil, fmt.Errorf("current branch does not match the expected pattern")
-	}
+	return parse(branch, domain.Target.ParseBranchName, "current branch does not match the expected pattern")
+}
+
+// Parse parses a tag name, following the tag format of its target.
+func Parse(name string) (*domain.Tag, error) {
+	return parse(name, domain.Target.ParseTagName, fmt.Sprintf("tag %s does not match the expected pattern", name))
+}
 
-	if t.Target, err = target.Get(parts[1]); err != nil {
-		return nil, err
+func parse(name string, parseName func(domain.Target, string) (string, bool), message string) (*domain.Tag, error) {
+	var lastErr error
+	for _, id := range target.GetIds() {
+		t, err := target.Get(id)
+		if err != nil {
+			return nil, err
+		}
+
+		rawVersion, ok := parseName(*t, name)
+		if !ok {
+			continue
+		}
+
+		// Formats may overlap, e.g. for targets web and web-admin, so the
+		// version decides which target the name belongs to.
+		v, err := version.Parse(t, rawVersion)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		return &domain.Tag{Target: t, Version: v}, nil
 	}
-	if t.Version, err = version.Parse(parts[2]); err != nil {
-		return nil, err
+
+	if lastErr != nil {
+		return nil, lastErr
 	}
 
-	return &t, nil
+	return nil, errors.New(message)
 }
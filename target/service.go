@@ -23,6 +23,15 @@ import (
 	"gotofu.com/mochi/domain"
 )
 
+func GetIds() []string {
+	var ids []string
+	for _, t := range config.Configuration.Targets {
+		ids = append(ids, t.Id)
+	}
+
+	return ids
+}
+
 func Get(id string) (*domain.Target, error) {
 	for _, t := range config.Configuration.Targets {
 		if t.Id == id {
@@ -32,3 +41,40 @@ func Get(id string) (*domain.Target, error) {
 
 	return nil, fmt.Errorf("target with ID %s not found", id)
 }
+
+func GetAll(ids []string) ([]*domain.Target, error) {
+	var targets []*domain.Target
+	for _, id := range ids {
+		t, err := Get(id)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+
+	return targets, nil
+}
+
+func Touched(paths []string) []*domain.Target {
+	var targets []*domain.Target
+	for i, t := range config.Configuration.Targets {
+		for _, path := range paths {
+			if t.Owns(path) {
+				targets = append(targets, &config.Configuration.Targets[i])
+				break
+			}
+		}
+	}
+
+	return targets
+}
+
+func Infer(workingDirectory string, stagedFiles []string) []*domain.Target {
+	if workingDirectory != "." {
+		if targets := Touched([]string{workingDirectory}); len(targets) > 0 {
+			return targets
+		}
+	}
+
+	return Touched(stagedFiles)
+}
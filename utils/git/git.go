@@ -22,6 +22,7 @@ import (
 	"log/slog"
 	"os/exec"
 	"strings"
+	"time"
 )
 
 func execGit(args ...string) (string, error) {
@@ -43,6 +44,25 @@ func execGit(args ...string) (string, error) {
 	return stdout.String(), nil
 }
 
+func splitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func RootDirectory() (string, error) {
+	if result, err := execGit("rev-parse", "--show-toplevel"); err != nil {
+		return "", fmt.Errorf("could not find repository root: %w", err)
+	} else {
+		return strings.TrimSpace(result), nil
+	}
+}
+
 func EnsureClean() error {
 	if status, err := execGit("status", "--porcelain"); err != nil {
 		return fmt.Errorf("could not check git status: %w", err)
@@ -61,12 +81,155 @@ func RevisionExists(rev string) bool {
 	return true
 }
 
-func LatestTagForTarget(target string) (string, error) {
-	latestGitTag, err := execGit("describe", "--tags", fmt.Sprintf(`--match=%s*`, target), "--abbrev=0", "HEAD")
+type TagRef struct {
+	Name   string
+	Date   time.Time
+	Commit string
+}
+
+// Tags lists the local tags matching the pattern. If merged is set, only the
+// tags reachable from that revision are listed.
+func Tags(pattern string, merged string) ([]TagRef, error) {
+	args := []string{"for-each-ref", "--format=%(refname:short)%00%(creatordate:iso-strict)%00%(objectname:short)%00%(*objectname:short)"}
+	if merged != "" {
+		args = append(args, fmt.Sprintf("--merged=%s", merged))
+	}
+
+	result, err := execGit(append(args, fmt.Sprintf("refs/tags/%s", pattern))...)
 	if err != nil {
-		return "", fmt.Errorf("could not get latest tag for target %s", target)
+		return nil, fmt.Errorf("could not list tags: %w", err)
+	}
+
+	var tags []TagRef
+	for _, line := range splitLines(result) {
+		fields := strings.Split(line, "\x00")
+		if len(fields) != 4 {
+			continue
+		}
+
+		tag := TagRef{Name: fields[0], Commit: fields[2]}
+		// Annotated tags point to a tag object, which is peeled to get the commit.
+		if fields[3] != "" {
+			tag.Commit = fields[3]
+		}
+		tag.Date, _ = time.Parse(time.RFC3339, fields[1])
+
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func TagAnnotation(tag string) (string, error) {
+	if !RevisionExists(fmt.Sprintf("refs/tags/%s", tag)) {
+		return "", fmt.Errorf("tag %s does not exist", tag)
+	}
+
+	if result, err := execGit("tag", "--list", "--format=%(contents)", tag); err != nil {
+		return "", fmt.Errorf("could not get annotation of tag %s: %w", tag, err)
+	} else {
+		return result, nil
+	}
+}
+
+// RemoteTags lists the tags on the remote, without fetching them. Their dates
+// are unknown.
+func RemoteTags(remote string, pattern string) ([]TagRef, error) {
+	result, err := execGit("ls-remote", "--tags", remote, fmt.Sprintf("refs/tags/%s", pattern))
+	if err != nil {
+		return nil, fmt.Errorf("could not list tags of remote %s: %w", remote, err)
+	}
+
+	var (
+		tags    []TagRef
+		indexes = map[string]int{}
+	)
+	for _, line := range splitLines(result) {
+		commit, ref, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+
+		name := strings.TrimPrefix(ref, "refs/tags/")
+		// Annotated tags are listed a second time, peeled to the commit.
+		if peeled, ok := strings.CutSuffix(name, "^{}"); ok {
+			if i, ok := indexes[peeled]; ok {
+				tags[i].Commit = commit
+			}
+			continue
+		}
+
+		indexes[name] = len(tags)
+		tags = append(tags, TagRef{Name: name, Commit: commit})
+	}
+
+	return tags, nil
+}
+
+// IsAncestor reports whether the commit is reachable from the revision. It is
+// false for commits which are not known locally.
+func IsAncestor(commit string, rev string) bool {
+	_, err := execGit("merge-base", "--is-ancestor", commit, rev)
+	return err == nil
+}
+
+func FileCreatedAt(path string) (time.Time, error) {
+	result, err := execGit("log", "--diff-filter=A", "--format=%cI", "-1", "--", path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not get creation date of %s: %w", path, err)
+	}
+
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return time.Time{}, fmt.Errorf("file %s is not committed", path)
+	}
+
+	return time.Parse(time.RFC3339, result)
+}
+
+func ChangedFiles(rev string, filter string) ([]string, error) {
+	// Paths are relative to the current directory, which is the project
+	// directory, and files outside of it are left out.
+	args := []string{"diff", "--name-only", "--no-renames", "--relative"}
+	if filter != "" {
+		args = append(args, fmt.Sprintf("--diff-filter=%s", filter))
+	}
+	args = append(args, fmt.Sprintf("%s...HEAD", rev))
+
+	result, err := execGit(args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not get changed files since %s: %w", rev, err)
+	}
+
+	return splitLines(result), nil
+}
+
+func StagedFiles() ([]string, error) {
+	result, err := execGit("diff", "--cached", "--name-only", "--no-renames", "--relative")
+	if err != nil {
+		return nil, fmt.Errorf("could not get staged files: %w", err)
+	}
+
+	return splitLines(result), nil
+}
+
+// CommitMessages returns the messages of the commits made since the merge
+// base of the revision and HEAD, the range ChangedFiles compares.
+func CommitMessages(rev string) (string, error) {
+	result, err := execGit("log", "--format=%B", "--right-only", fmt.Sprintf("%s...HEAD", rev))
+	if err != nil {
+		return "", fmt.Errorf("could not get commit messages since %s: %w", rev, err)
+	}
+
+	return result, nil
+}
+
+func UserName() (string, error) {
+	if result, err := execGit("config", "user.name"); err != nil {
+		return "", fmt.Errorf("could not get user name: %w", err)
+	} else {
+		return strings.TrimSpace(result), nil
 	}
-	return strings.TrimSpace(latestGitTag), nil
 }
 
 func CurrentBranch() (string, error) {
@@ -101,8 +264,8 @@ func Checkout(branch string, base string) error {
 	return nil
 }
 
-func Tag(tag string) error {
-	if _, err := execGit("tag", tag, "-m", tag); err != nil {
+func Tag(tag string, message string) error {
+	if _, err := execGit("tag", "--cleanup=whitespace", "-m", message, tag); err != nil {
 		return fmt.Errorf("could not tag %s: %w", tag, err)
 	}
 
@@ -117,17 +280,24 @@ func Add(path string) error {
 	return nil
 }
 
-func Commit(message string) error {
-	if _, err := execGit("commit", "-m", message); err != nil {
+func Commit(message string, allowEmpty bool) error {
+	args := []string{"commit", "-m", message}
+	if allowEmpty {
+		args = append(args, "--allow-empty")
+	}
+
+	if _, err := execGit(args...); err != nil {
 		return fmt.Errorf("could not commit: %w", err)
 	}
 
 	return nil
 }
 
-func Push() error {
-	if _, err := execGit("push", "--follow-tags"); err != nil {
-		return fmt.Errorf("could not push: %w", err)
+// Push pushes the refs to the remote atomically, so that either all of them
+// are updated or none is.
+func Push(remote string, refs []string) error {
+	if _, err := execGit(append([]string{"push", "--atomic", remote}, refs...)...); err != nil {
+		return fmt.Errorf("could not push to %s: %w", remote, err)
 	}
 
 	return nil
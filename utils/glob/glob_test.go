@@ -0,0 +1,69 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		// Plain segments behave like path.Match.
+		{"api/main.go", "api/main.go", true},
+		{"api/*.go", "api/main.go", true},
+		{"api/*.go", "api/cmd/main.go", false},
+		{"api/*", "api", false},
+		{"api/?.go", "api/a.go", true},
+
+		// "**" at the start.
+		{"**/main.go", "main.go", true},
+		{"**/main.go", "api/cmd/main.go", true},
+		{"**/main.go", "api/cmd/main_test.go", false},
+
+		// "**" in the middle.
+		{"api/**/main.go", "api/main.go", true},
+		{"api/**/main.go", "api/cmd/server/main.go", true},
+		{"api/**/main.go", "web/cmd/main.go", false},
+		{"api/**/cmd/*.go", "api/a/b/cmd/main.go", true},
+
+		// "**" at the end, matching the directory itself too, as used to
+		// infer the target from the working directory.
+		{"api/**", "api", true},
+		{"api/**", "api/main.go", true},
+		{"api/**", "api/cmd/server", true},
+		{"api/**", "api-gateway/main.go", false},
+		{"api/**", "web", false},
+		{"**", "anything/at/all", true},
+
+		// Leading and trailing slashes are ignored.
+		{"api/**", "api/", true},
+		{"api/**", "api/cmd/", true},
+		{"api/", "api", true},
+		{"/api/*.go", "api/main.go", true},
+		{"api/", "api/main.go", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			if got := Match(tt.pattern, tt.name); got != tt.want {
+				t.Errorf("Match(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
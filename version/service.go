@@ -18,69 +18,116 @@ package version
 
 import (
 	"fmt"
-	"regexp"
-	"strconv"
+	"log/slog"
 	"time"
 
+	"gotofu.com/mochi/config"
 	"gotofu.com/mochi/domain"
 	"gotofu.com/mochi/utils/git"
 )
 
-var versionRegex = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
-
-func Parse(version string) (*domain.Version, error) {
-	var (
-		v   domain.Version
-		err error
-	)
+func Parse(target *domain.Target, version string) (*domain.Version, error) {
+	return target.Scheme().Parse(version)
+}
 
-	parts := versionRegex.FindStringSubmatch(version)
-	if len(parts) != 4 {
-		return nil, fmt.Errorf("version is not in the format year.week.patch")
+// Next returns the version following the latest one. While the latest version
+// is a pre-release, its final version is the next one, and further
+// pre-releases with the same label increment its counter.
+func Next(target *domain.Target, latestVersion *domain.Version, bump domain.BumpLevel, preLabel string) *domain.Version {
+	if latestVersion != nil && latestVersion.IsPrerelease() {
+		final := latestVersion.Final()
+
+		switch {
+		case preLabel == "":
+			return final
+		case preLabel == latestVersion.PreLabel:
+			return final.WithPrerelease(preLabel, latestVersion.PreNumber+1)
+		default:
+			return final.WithPrerelease(preLabel, 1)
+		}
 	}
 
-	if v.Year, err = strconv.Atoi(parts[1]); err != nil {
-		return nil, fmt.Errorf("year is not a valid number")
-	}
-	if v.Week, err = strconv.Atoi(parts[2]); err != nil {
-		return nil, fmt.Errorf("week is not a valid number")
+	nextVersion := target.Scheme().Next(latestVersion, time.Now(), bump)
+	if preLabel != "" {
+		nextVersion = nextVersion.WithPrerelease(preLabel, 1)
 	}
-	if v.Patch, err = strconv.Atoi(parts[3]); err != nil {
-		return nil, fmt.Errorf("patch is not a valid number")
+
+	return nextVersion
+}
+
+// Hotfix returns the first patch version following the base version which
+// was not released yet, regardless of the current date.
+func Hotfix(target *domain.Target, base *domain.Version) *domain.Version {
+	next := base.Final()
+	for {
+		next = &domain.Version{Major: next.Major, Minor: next.Minor, Patch: next.Patch + 1}
+		if !git.RevisionExists(fmt.Sprintf("refs/tags/%s", target.TagName(*next))) {
+			return next
+		}
 	}
+}
 
-	if err = v.Validate(); err != nil {
+// Latest returns the highest version among the tags of the target. Tags of
+// other targets matching the same pattern, e.g. api-gateway@1.0.0 for target
+// api, are ignored as they do not parse with the tag format of the target.
+func Latest(target *domain.Target) (*domain.Version, error) {
+	tags, err := discoverTags(target)
+	if err != nil {
 		return nil, err
 	}
 
-	return &v, nil
-}
-
-func Next(target *domain.Target, latestVersion *domain.Version) *domain.Version {
-	currentYear, currentWeek := time.Now().ISOWeek()
-	nextVersion := &domain.Version{
-		Year:  currentYear,
-		Week:  currentWeek,
-		Patch: 0,
+	var latestVersion *domain.Version
+	for _, tag := range tags {
+		rawVersion, ok := target.ParseTagName(tag.Name)
+		if !ok {
+			continue
+		}
+
+		v, err := Parse(target, rawVersion)
+		if err != nil {
+			slog.Debug("Ignoring tag not matching the versioning scheme.", "tag", tag.Name, "error", err)
+			continue
+		}
+
+		if latestVersion == nil || v.Compare(latestVersion) > 0 {
+			latestVersion = v
+		}
 	}
 
-	if latestVersion != nil && latestVersion.IsSameWeek(nextVersion) {
-		nextVersion = latestVersion.Bump()
+	if latestVersion == nil {
+		return nil, fmt.Errorf("no release found for target %s", target.Id)
 	}
 
-	return nextVersion
+	return latestVersion, nil
 }
 
-func Latest(target *domain.Target) (*domain.Version, error) {
-	latestGitTag, err := git.LatestTagForTarget(target.Id)
-	if err != nil {
-		return nil, err
+func discoverTags(target *domain.Target) ([]git.TagRef, error) {
+	discovery := config.Configuration.TagDiscovery
+
+	merged := ""
+	if discovery.ReachableOnly {
+		merged = config.Configuration.BaseBranch
 	}
 
-	latestVersion, err := Parse(string(latestGitTag))
+	tags, err := git.Tags(target.TagPattern(), merged)
 	if err != nil {
 		return nil, err
 	}
 
-	return latestVersion, nil
+	if discovery.IncludeRemote {
+		remoteTags, err := git.RemoteTags(config.Configuration.Remote, target.TagPattern())
+		if err != nil {
+			return nil, err
+		}
+
+		for _, tag := range remoteTags {
+			// Remote tags whose commit was not fetched cannot be checked.
+			if discovery.ReachableOnly && !git.IsAncestor(tag.Commit, merged) {
+				continue
+			}
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags, nil
 }
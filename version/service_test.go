@@ -0,0 +1,70 @@
+/*
+Copyright © 2024-present The Mochi Authors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package version
+
+import (
+	"testing"
+
+	"gotofu.com/mochi/domain"
+)
+
+func TestNextPrerelease(t *testing.T) {
+	target := &domain.Target{Id: "web", Versioning: "semver"}
+
+	tests := []struct {
+		name     string
+		latest   *domain.Version
+		bump     domain.BumpLevel
+		preLabel string
+		want     string
+	}{
+		{
+			name:     "first pre-release",
+			latest:   &domain.Version{Major: 1, Minor: 2, Patch: 3},
+			bump:     domain.BumpMinor,
+			preLabel: "rc",
+			want:     "1.3.0-rc.1",
+		},
+		{
+			name:     "next pre-release with the same label",
+			latest:   &domain.Version{Major: 1, Minor: 3, PreLabel: "rc", PreNumber: 9},
+			bump:     domain.BumpMajor,
+			preLabel: "rc",
+			want:     "1.3.0-rc.10",
+		},
+		{
+			name:     "pre-release with another label",
+			latest:   &domain.Version{Major: 1, Minor: 3, PreLabel: "beta", PreNumber: 2},
+			preLabel: "rc",
+			want:     "1.3.0-rc.1",
+		},
+		{
+			name:   "final after a pre-release",
+			latest: &domain.Version{Major: 1, Minor: 3, PreLabel: "rc", PreNumber: 2},
+			bump:   domain.BumpMajor,
+			want:   "1.3.0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Next(target, tt.latest, tt.bump, tt.preLabel); got.String() != tt.want {
+				t.Errorf("Next() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}